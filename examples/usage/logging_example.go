@@ -15,12 +15,18 @@ type ExampleService interface {
 // 服务实现
 type ExampleServiceImpl struct {
 	Name string
+
+	// 由容器通过LoggerAware接口注入，已带有bean名称字段
+	logger ioc.Logger
+}
+
+// SetLogger实现LoggerAware接口
+func (s *ExampleServiceImpl) SetLogger(logger ioc.Logger) {
+	s.logger = logger
 }
 
 func (s *ExampleServiceImpl) DoSomething() error {
-	// 获取IoC容器的日志记录器
-	logger := ioc.GetContainerLogger()
-	logger.Info("服务正在执行操作",
+	s.logger.Info("服务正在执行操作",
 		zap.String("serviceName", s.Name))
 
 	return nil
@@ -28,8 +34,7 @@ func (s *ExampleServiceImpl) DoSomething() error {
 
 // PostConstruct实现
 func (s *ExampleServiceImpl) PostConstruct() error {
-	logger := ioc.GetContainerLogger()
-	logger.Info("ExampleService初始化",
+	s.logger.Info("ExampleService初始化",
 		zap.String("serviceName", s.Name))
 	return nil
 }
@@ -0,0 +1,125 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// WithAliasWarnings 通过别名获取bean时记录弃用警告，默认关闭
+// 用于重命名bean的迁移期间找出仍在使用旧名称的代码
+func WithAliasWarnings(enabled bool) ContainerOption {
+	return func(c *containerImpl) {
+		c.aliasWarnings = enabled
+	}
+}
+
+// RegisterAlias 为bean注册别名，通过别名获取bean与通过原名称获取得到同一个实例
+// 别名的目标可以是另一个别名，目标bean可以在别名之后注册，容器初始化时校验目标是否存在；
+// 容器初始化后注册的别名立即校验目标是否存在
+func (c *containerImpl) RegisterAlias(alias string, target string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if alias == "" || target == "" {
+		return errors.New("alias and target cannot be empty")
+	}
+	if _, exists := c.beans[alias]; exists {
+		return fmt.Errorf("bean with name '%s' already exists", alias)
+	}
+	if existing, exists := c.aliases[alias]; exists {
+		return fmt.Errorf("alias '%s' already refers to '%s'", alias, existing)
+	}
+
+	// 检查别名链是否形成循环
+	chain := []string{alias}
+	for name := target; ; {
+		chain = append(chain, name)
+		if name == alias {
+			return fmt.Errorf("circular alias detected: %s", strings.Join(chain, " -> "))
+		}
+		next, ok := c.aliases[name]
+		if !ok {
+			break
+		}
+		name = next
+	}
+
+	if c.initialized {
+		if _, exists := c.beans[c.followAlias(target)]; !exists {
+			return fmt.Errorf("alias '%s' refers to unknown bean '%s'", alias, target)
+		}
+	}
+
+	c.aliases[alias] = target
+	c.logger.Debug("成功注册bean别名",
+		zap.String("alias", alias),
+		zap.String("target", target))
+	return nil
+}
+
+// GetAliases 返回所有别名及其最终指向的bean名称
+func (c *containerImpl) GetAliases() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	aliases := make(map[string]string, len(c.aliases))
+	for alias := range c.aliases {
+		aliases[alias] = c.followAlias(alias)
+	}
+	return aliases
+}
+
+// resolveAlias 返回别名最终指向的bean名称，不是别名时原样返回
+func (c *containerImpl) resolveAlias(name string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.canonicalName(name)
+}
+
+// canonicalName 返回别名最终指向的bean名称，启用别名警告时记录日志，调用方需持有锁
+func (c *containerImpl) canonicalName(name string) string {
+	if _, ok := c.aliases[name]; !ok {
+		return name
+	}
+
+	target := c.followAlias(name)
+	if c.aliasWarnings {
+		c.logger.Warn("通过别名获取bean，该名称已弃用",
+			zap.String("alias", name),
+			zap.String("beanName", target))
+	}
+	return target
+}
+
+// followAlias 沿别名链查找最终的bean名称，调用方需持有锁
+func (c *containerImpl) followAlias(name string) string {
+	for {
+		next, ok := c.aliases[name]
+		if !ok {
+			return name
+		}
+		name = next
+	}
+}
+
+// checkAliases 检查所有别名最终指向的bean是否存在，调用方需持有锁
+func (c *containerImpl) checkAliases() error {
+	aliases := make([]string, 0, len(c.aliases))
+	for alias := range c.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	var errs []error
+	for _, alias := range aliases {
+		target := c.followAlias(alias)
+		if _, exists := c.beans[target]; !exists {
+			errs = append(errs, fmt.Errorf("alias '%s' refers to unknown bean '%s'", alias, target))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,145 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// RegisterAssistedFactory 注册辅助注入工厂
+// factoryType是指向工厂函数类型的指针，例如 new(QuotaCheckerFactory)，其中
+//
+//	type QuotaCheckerFactory func(tenantID string) (QuotaChecker, error)
+//
+// constructor的前几个参数与工厂函数的参数一一对应，由调用方在运行时传入，
+// 其余参数从容器中解析。容器中名为name的bean就是该工厂函数，
+// 每次调用都会创建新实例，并像Prototype一样完成依赖注入和PostConstruct
+//
+// 工厂函数类型可以只返回实例，此时构造函数不能返回error；但解析依赖或调用PostConstruct仍可能失败，
+// 失败时工厂函数会panic，panic的值是包装了失败原因的error。需要处理这类错误时，工厂函数类型应返回error
+func (c *containerImpl) RegisterAssistedFactory(name string, factoryType interface{}, constructor interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name == "" {
+		return errors.New("bean name cannot be empty")
+	}
+	if factoryType == nil || constructor == nil {
+		return errors.New("factory type and constructor cannot be nil")
+	}
+
+	ft := reflect.TypeOf(factoryType)
+	if ft.Kind() != reflect.Ptr || ft.Elem().Kind() != reflect.Func {
+		return fmt.Errorf("factory type must be a pointer to a function type, got %s", ft)
+	}
+	ft = ft.Elem()
+
+	cv := reflect.ValueOf(constructor)
+	if err := validateAssistedFactory(ft, cv.Type()); err != nil {
+		return err
+	}
+
+	if err := c.checkBeanName(name); err != nil {
+		return err
+	}
+
+	factory := reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		result, err := c.callAssisted(cv, args, ft.Out(0))
+
+		if ft.NumOut() == 1 {
+			if err != nil {
+				panic(fmt.Errorf("assisted factory '%s' failed: %w", name, err))
+			}
+			return []reflect.Value{result}
+		}
+
+		errVal := reflect.Zero(errorType)
+		if err != nil {
+			result = reflect.Zero(ft.Out(0))
+			errVal = reflect.ValueOf(&err).Elem()
+		}
+		return []reflect.Value{result, errVal}
+	})
+
+	// 工厂函数本身不需要注入和初始化
+	err := c.insertBean(&BeanDefinition{
+		Name:        name,
+		Type:        ft,
+		Instance:    factory.Interface(),
+		Scope:       Singleton,
+		source:      callerSite(),
+		injected:    true,
+		initialized: true,
+	}, "", false)
+	if err != nil {
+		return err
+	}
+	c.logger.Debug("成功注册辅助注入工厂",
+		zap.String("beanName", name),
+		zap.String("type", ft.String()))
+	return nil
+}
+
+// callAssisted 使用运行时参数和容器中的依赖调用构造函数，并初始化创建的实例
+func (c *containerImpl) callAssisted(cv reflect.Value, args []reflect.Value, resultType reflect.Type) (reflect.Value, error) {
+	ct := cv.Type()
+	r := c.currentResolver(nil)
+
+	callArgs := append([]reflect.Value(nil), args...)
+	for i := len(args); i < ct.NumIn(); i++ {
+		arg, err := c.resolveParam(ct.In(i), r)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("error resolving parameter %d of constructor %s: %w", i, ct, err)
+		}
+		callArgs = append(callArgs, arg)
+	}
+
+	out := cv.Call(callArgs)
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, out[1].Interface().(error)
+	}
+
+	instance := out[0].Interface()
+	if instance == nil {
+		return reflect.Value{}, fmt.Errorf("constructor %s returned nil", ct)
+	}
+
+	// 结构体实例像Prototype一样注入依赖并调用PostConstruct
+	if reflect.Indirect(reflect.ValueOf(instance)).Kind() == reflect.Struct {
+		if err := c.initializePrototype(instance, nil, nil); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	return assignableValue(instance, resultType)
+}
+
+// validateAssistedFactory 校验工厂函数类型与构造函数是否匹配
+func validateAssistedFactory(ft reflect.Type, ct reflect.Type) error {
+	if err := validateConstructor(ct); err != nil {
+		return err
+	}
+	if ft.IsVariadic() {
+		return fmt.Errorf("factory type %s must not be variadic", ft)
+	}
+	if ft.NumOut() == 0 || ft.NumOut() > 2 || (ft.NumOut() == 2 && ft.Out(1) != errorType) {
+		return fmt.Errorf("factory type %s must return a value and an optional error", ft)
+	}
+	if ct.NumOut() == 2 && ft.NumOut() == 1 {
+		return fmt.Errorf("factory type %s must return an error because constructor %s does", ft, ct)
+	}
+	if !ct.Out(0).AssignableTo(ft.Out(0)) && ct.Out(0).Kind() != reflect.Interface {
+		return fmt.Errorf("constructor result %s is not assignable to %s", ct.Out(0), ft.Out(0))
+	}
+	if ct.NumIn() < ft.NumIn() {
+		return fmt.Errorf("constructor %s must accept the %d parameters of factory type %s", ct, ft.NumIn(), ft)
+	}
+	for i := 0; i < ft.NumIn(); i++ {
+		if !ft.In(i).AssignableTo(ct.In(i)) {
+			return fmt.Errorf("parameter %d of factory type %s is not assignable to %s", i, ft, ct.In(i))
+		}
+	}
+	return nil
+}
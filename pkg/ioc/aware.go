@@ -0,0 +1,75 @@
+package ioc
+
+import (
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// ContainerAware 由需要访问所属容器的bean实现
+// 容器在依赖注入后、PostConstruct之前回调，传入的是bean所属的容器而不是全局容器
+type ContainerAware interface {
+	SetContainer(container Container)
+}
+
+// BeanNameAware 由需要知道自身注册名称的bean实现
+type BeanNameAware interface {
+	SetBeanName(name string)
+}
+
+// LoggerAware 由需要日志记录器的bean实现
+// 传入的日志记录器已带有 bean 字段，值为bean的注册名称
+type LoggerAware interface {
+	SetLogger(logger Logger)
+}
+
+// Container接口的反射类型，字段或参数声明为该类型时注入所属容器
+var containerType = reflect.TypeOf((*Container)(nil)).Elem()
+
+// awareMethods 是各Aware接口的回调方法，不作为普通setter注入
+var awareMethods = map[string]bool{
+	"SetContainer": true,
+	"SetBeanName":  true,
+	"SetLogger":    true,
+}
+
+// invokeAware 回调实例实现的Aware接口，bean为nil时不回调BeanNameAware
+func (c *containerImpl) invokeAware(instance interface{}, bean *BeanDefinition) {
+	if aware, ok := instance.(ContainerAware); ok {
+		aware.SetContainer(c)
+	}
+
+	if bean == nil {
+		if aware, ok := instance.(LoggerAware); ok {
+			aware.SetLogger(c.logger)
+		}
+		return
+	}
+
+	if aware, ok := instance.(BeanNameAware); ok {
+		aware.SetBeanName(bean.Name)
+	}
+
+	if aware, ok := instance.(LoggerAware); ok {
+		aware.SetLogger(c.logger.With(zap.String("bean", bean.Name)))
+	}
+}
+
+// isAwareMethod 判断方法是否为实例所实现的Aware接口的回调方法
+func isAwareMethod(instance interface{}, method string) bool {
+	if !awareMethods[method] {
+		return false
+	}
+
+	switch method {
+	case "SetContainer":
+		_, ok := instance.(ContainerAware)
+		return ok
+	case "SetBeanName":
+		_, ok := instance.(BeanNameAware)
+		return ok
+	default:
+		_, ok := instance.(LoggerAware)
+		return ok
+	}
+}
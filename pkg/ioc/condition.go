@@ -0,0 +1,309 @@
+package ioc
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ConditionContext 提供条件判断时可以查询的容器状态
+type ConditionContext interface {
+	// HasBean 判断是否已注册指定名称（或别名）的bean
+	HasBean(name string) bool
+
+	// HasBeanOfType 判断是否已注册类型与t匹配的bean
+	// FactoryBean按其生产的对象类型匹配，工厂函数按声明的接口或构造函数的返回类型匹配；
+	// 仍无法确定类型的工厂bean不参与匹配，相关条件在诊断信息中标记为Unknown
+	HasBeanOfType(t reflect.Type) bool
+
+	// Property 获取容器属性
+	Property(key string) (string, bool)
+}
+
+// Condition 接口定义了注册bean的条件
+// 条件在容器初始化前按注册顺序求值，后注册的条件可以看到先前满足条件注册的bean；
+// 实现fmt.Stringer时，其返回值用于诊断信息
+type Condition interface {
+	Matches(ctx ConditionContext) bool
+}
+
+// ConditionFunc 将函数适配为Condition
+type ConditionFunc func(ctx ConditionContext) bool
+
+// Matches 调用函数本身
+func (f ConditionFunc) Matches(ctx ConditionContext) bool {
+	return f(ctx)
+}
+
+// ConditionResult 表示单个条件的求值结果
+type ConditionResult struct {
+	// 条件的描述
+	Condition string
+
+	// 是否满足
+	Matched bool
+
+	// 求值结果是否无法确定，按类型判断时存在类型尚未确定的工厂bean
+	Unknown bool
+
+	// 类型尚未确定、未参与按类型判断的工厂bean
+	UntypedBeans []string
+}
+
+// ConditionOutcome 表示一次条件注册的结果，用于解释bean为何注册或未注册
+type ConditionOutcome struct {
+	// bean名称
+	Bean string
+
+	// 是否已注册
+	Registered bool
+
+	// 所有条件的求值结果
+	Conditions []ConditionResult
+}
+
+// pendingRegistration 等待容器初始化时求值条件的注册
+type pendingRegistration struct {
+	bean       *BeanDefinition
+	key        string
+	typed      bool
+	conditions []Condition
+}
+
+// WithProperties 设置容器属性，供OnProperty条件使用
+func WithProperties(properties map[string]string) ContainerOption {
+	return func(c *containerImpl) {
+		for k, v := range properties {
+			c.properties[k] = v
+		}
+	}
+}
+
+// When 只有所有条件都满足时才注册bean
+// 容器初始化前注册的条件bean在Init开始时求值，初始化后注册的条件bean立即求值
+func When(conditions ...Condition) RegisterOption {
+	return func(r *registration) {
+		r.conditions = append(r.conditions, conditions...)
+	}
+}
+
+// OnMissingBean 在不存在指定bean时满足
+// 参数为字符串时按名称判断，为指向接口的指针（如 new(LogService)）时按接口判断，其他值按其类型判断
+func OnMissingBean(target interface{}) Condition {
+	return &beanCondition{target: target, missing: true}
+}
+
+// OnBean 在指定的bean都存在时满足，参数含义与OnMissingBean相同
+func OnBean(targets ...interface{}) Condition {
+	conditions := make(allConditions, len(targets))
+	for i, target := range targets {
+		conditions[i] = &beanCondition{target: target}
+	}
+	return conditions
+}
+
+// OnProperty 在容器属性key的值等于value时满足
+func OnProperty(key string, value string) Condition {
+	return &propertyCondition{key: key, value: value}
+}
+
+// OnEnv 在设置了环境变量name时满足
+func OnEnv(name string) Condition {
+	return envCondition(name)
+}
+
+// beanCondition 按bean是否存在判断
+type beanCondition struct {
+	target  interface{}
+	missing bool
+}
+
+func (b *beanCondition) Matches(ctx ConditionContext) bool {
+	var exists bool
+	if name, ok := b.target.(string); ok {
+		exists = ctx.HasBean(name)
+	} else if t := conditionType(b.target); t != nil {
+		exists = ctx.HasBeanOfType(t)
+	}
+	return exists != b.missing
+}
+
+func (b *beanCondition) String() string {
+	target := fmt.Sprint(b.target)
+	if _, ok := b.target.(string); !ok {
+		target = fmt.Sprint(conditionType(b.target))
+	}
+	if b.missing {
+		return "OnMissingBean(" + target + ")"
+	}
+	return "OnBean(" + target + ")"
+}
+
+// conditionType 返回条件中指定的类型，指向接口的指针返回接口类型
+func conditionType(target interface{}) reflect.Type {
+	t := reflect.TypeOf(target)
+	if t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+		return t.Elem()
+	}
+	return t
+}
+
+// allConditions 所有条件都满足时满足
+type allConditions []Condition
+
+func (a allConditions) Matches(ctx ConditionContext) bool {
+	for _, condition := range a {
+		if !condition.Matches(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a allConditions) String() string {
+	descriptions := make([]string, len(a))
+	for i, condition := range a {
+		descriptions[i] = describeCondition(condition)
+	}
+	return strings.Join(descriptions, " && ")
+}
+
+// propertyCondition 按容器属性判断
+type propertyCondition struct {
+	key   string
+	value string
+}
+
+func (p *propertyCondition) Matches(ctx ConditionContext) bool {
+	value, ok := ctx.Property(p.key)
+	return ok && value == p.value
+}
+
+func (p *propertyCondition) String() string {
+	return fmt.Sprintf("OnProperty(%s=%s)", p.key, p.value)
+}
+
+// envCondition 按环境变量判断
+type envCondition string
+
+func (e envCondition) Matches(ConditionContext) bool {
+	_, ok := os.LookupEnv(string(e))
+	return ok
+}
+
+func (e envCondition) String() string {
+	return "OnEnv(" + string(e) + ")"
+}
+
+// describeCondition 返回条件在诊断信息中的描述
+func describeCondition(condition Condition) string {
+	if s, ok := condition.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", condition)
+}
+
+// conditionContext 在持有容器锁时查询容器状态
+type conditionContext struct {
+	c *containerImpl
+
+	// 按类型判断时遇到的类型尚未确定的工厂bean
+	untyped []string
+}
+
+func (ctx *conditionContext) HasBean(name string) bool {
+	bean, exists := ctx.c.beans[ctx.c.followAlias(name)]
+	return exists && !bean.Private
+}
+
+func (ctx *conditionContext) HasBeanOfType(t reflect.Type) bool {
+	if len(ctx.c.matchCandidates(t)) > 0 {
+		return true
+	}
+
+	// 没有匹配的bean时，记录可能生产该类型但类型尚未确定的工厂bean
+	for name, bean := range ctx.c.beans {
+		// 声明了接口的工厂bean按声明的接口参与匹配，视为类型已确定
+		if bean.Private || bean.Type != nil || len(bean.Interfaces) > 0 {
+			continue
+		}
+		if _, ok := bean.Instance.(FactoryBean); ok {
+			continue
+		}
+		if !containsString(ctx.untyped, name) {
+			ctx.untyped = append(ctx.untyped, name)
+		}
+	}
+	sort.Strings(ctx.untyped)
+	return false
+}
+
+func (ctx *conditionContext) Property(key string) (string, bool) {
+	value, ok := ctx.c.properties[key]
+	return value, ok
+}
+
+// evaluateConditions 求值bean的注册条件并记录结果，返回是否所有条件都满足，调用方需持有锁
+func (c *containerImpl) evaluateConditions(bean *BeanDefinition, conditions []Condition) bool {
+	outcome := ConditionOutcome{Bean: bean.Name, Registered: true}
+	for _, condition := range conditions {
+		ctx := &conditionContext{c: c}
+		result := ConditionResult{
+			Condition: describeCondition(condition),
+			Matched:   condition.Matches(ctx),
+		}
+		if len(ctx.untyped) > 0 {
+			result.Unknown = true
+			result.UntypedBeans = ctx.untyped
+			c.logger.Warn("条件求值时存在类型尚未确定的工厂bean，结果可能不准确",
+				zap.String("beanName", bean.Name),
+				zap.String("condition", result.Condition),
+				zap.Strings("untypedBeans", ctx.untyped))
+		}
+		outcome.Conditions = append(outcome.Conditions, result)
+		if !result.Matched {
+			outcome.Registered = false
+		}
+	}
+	c.conditionOutcomes = append(c.conditionOutcomes, outcome)
+
+	if outcome.Registered {
+		c.logger.Debug("注册条件满足，注册bean",
+			zap.String("beanName", bean.Name),
+			zap.Any("conditions", outcome.Conditions))
+	} else {
+		c.logger.Info("注册条件不满足，跳过注册bean",
+			zap.String("beanName", bean.Name),
+			zap.Any("conditions", outcome.Conditions))
+	}
+	return outcome.Registered
+}
+
+// registerPending 按注册顺序求值所有等待中的条件注册，调用方需持有锁
+func (c *containerImpl) registerPending() error {
+	pending := c.pending
+	c.pending = nil
+
+	for _, p := range pending {
+		if !c.evaluateConditions(p.bean, p.conditions) {
+			continue
+		}
+		if err := c.addBean(p.bean, p.key, p.typed); err != nil {
+			// 注册失败时修正诊断结果
+			c.conditionOutcomes[len(c.conditionOutcomes)-1].Registered = false
+			return err
+		}
+	}
+	return nil
+}
+
+// GetConditionReport 返回所有条件注册的结果，按求值顺序排列
+func (c *containerImpl) GetConditionReport() []ConditionOutcome {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]ConditionOutcome(nil), c.conditionOutcomes...)
+}
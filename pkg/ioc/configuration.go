@@ -0,0 +1,226 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// BeanMethodNamer 由配置类实现，用于自定义bean方法对应的bean名称
+// 返回空字符串表示该方法不注册为bean
+type BeanMethodNamer interface {
+	BeanMethodName(method string) string
+}
+
+// BeanMethodLister 由配置类实现，只注册列出的方法，不再注册其他导出方法
+// 列出的方法不存在或签名无效时注册失败
+type BeanMethodLister interface {
+	BeanMethods() []string
+}
+
+// RegisterConfiguration 注册配置类
+// 配置类本身注册为单例bean，名称为首字母小写的类型名，可以通过inject标签注入依赖；
+// 配置类的每个导出方法如果返回 T 或 (T, error)，都注册为单例bean，
+// 名称默认为首字母小写的方法名，方法的参数从容器中解析。例如：
+//
+//	func (c *DataConfig) ProductRepository(cfg *config.Config) (product.ProductRepository, error)
+//
+// 会注册名为 productRepository 的bean。实现BeanMethodNamer可以自定义名称或跳过方法，
+// 实现BeanMethodLister可以只注册列出的方法，两个接口的方法本身不会注册为bean。
+// 所有bean方法校验通过后才会写入容器，容器初始化后注册时，任一bean创建失败都会撤销本次注册
+func (c *containerImpl) RegisterConfiguration(configuration interface{}) error {
+	c.mu.Lock()
+	live, err := c.registerConfiguration(configuration)
+	c.mu.Unlock()
+
+	// 撤销注册时销毁已经创建的bean
+	for _, bean := range live {
+		if destroyErr := c.destroyInstance(bean.Name, bean); destroyErr != nil {
+			err = errors.Join(err, destroyErr)
+		}
+	}
+	return err
+}
+
+// configurationMethod 表示配置类中声明的一个bean方法
+type configurationMethod struct {
+	// bean名称，返回结果对象时忽略
+	name string
+
+	// 绑定到配置类实例的方法
+	method reflect.Value
+}
+
+// registerConfiguration 校验并注册配置类，调用方需持有锁
+// 注册失败时返回被撤销的已初始化bean，由调用方在释放锁后销毁
+func (c *containerImpl) registerConfiguration(configuration interface{}) ([]*BeanDefinition, error) {
+	if configuration == nil {
+		return nil, errors.New("cannot register nil configuration")
+	}
+
+	val := reflect.ValueOf(configuration)
+	t := val.Type()
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("configuration must be a pointer to struct, got %s", t)
+	}
+
+	configName := lowerFirst(t.Elem().Name())
+	methods, names, err := c.configurationMethods(configuration, configName)
+	if err != nil {
+		return nil, err
+	}
+
+	// 所有名称校验通过后再写入容器
+	for _, name := range names {
+		if err := c.checkBeanName(name); err != nil {
+			return nil, err
+		}
+	}
+
+	configBean := &BeanDefinition{
+		Name:     configName,
+		Type:     t,
+		Instance: configuration,
+		Scope:    Singleton,
+		source:   callerSite(),
+	}
+	if err := c.insertBean(configBean, "", false); err != nil {
+		return nil, err
+	}
+
+	// 调用bean方法之前确保配置类已完成依赖注入
+	prepare := func(from *initFrame) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.injectBeanDependencies(configName, configBean, from)
+	}
+
+	var providers []*constructorProvider
+	for _, m := range methods {
+		p := &constructorProvider{fn: m.method, scope: Singleton, prepare: prepare}
+		providers = append(providers, p)
+		if live, err := c.registerProvider(m.name, p); err != nil {
+			return append(live, c.rollbackConfiguration(names, providers)...), err
+		}
+	}
+
+	c.logger.Debug("成功注册配置类",
+		zap.String("beanName", configName),
+		zap.Int("beanMethods", len(methods)))
+	return nil, nil
+}
+
+// configurationMethods 收集并校验配置类的bean方法，返回bean方法和将要注册的所有bean名称，调用方需持有锁
+func (c *containerImpl) configurationMethods(configuration interface{}, configName string) ([]configurationMethod, []string, error) {
+	val := reflect.ValueOf(configuration)
+	t := val.Type()
+
+	lister, isLister := configuration.(BeanMethodLister)
+	namer, isNamer := configuration.(BeanMethodNamer)
+
+	// 未实现BeanMethodLister时候选所有导出方法，跳过BeanMethodNamer的方法
+	var candidates []string
+	if isLister {
+		candidates = lister.BeanMethods()
+	} else {
+		for i := 0; i < t.NumMethod(); i++ {
+			name := t.Method(i).Name
+			if isNamer && name == "BeanMethodName" {
+				continue
+			}
+			candidates = append(candidates, name)
+		}
+	}
+
+	var methods []configurationMethod
+	names := []string{configName}
+	declared := func(name string) error {
+		if containsString(names, name) {
+			return fmt.Errorf("bean with name '%s' already exists", name)
+		}
+		names = append(names, name)
+		return nil
+	}
+
+	for _, methodName := range candidates {
+		name := lowerFirst(methodName)
+		if isNamer {
+			name = namer.BeanMethodName(methodName)
+		}
+		if name == "" {
+			continue
+		}
+
+		m := val.MethodByName(methodName)
+		if !m.IsValid() {
+			return nil, nil, fmt.Errorf("configuration %s has no exported method '%s'", t, methodName)
+		}
+
+		// 只有列出的方法签名无效时报错，其他签名无效的导出方法不是bean方法
+		if err := validateConstructor(m.Type()); err != nil {
+			if !isLister {
+				continue
+			}
+			return nil, nil, fmt.Errorf("bean method '%s' of configuration %s: %w", methodName, t, err)
+		}
+
+		// 结果对象的每个字段分别注册为bean
+		if resultType := m.Type().Out(0); isResultObject(resultType) {
+			fields, _, err := resultObjectFields(resultType)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, f := range fields {
+				if err := declared(f.name); err != nil {
+					return nil, nil, err
+				}
+			}
+		} else if err := declared(name); err != nil {
+			return nil, nil, err
+		}
+
+		methods = append(methods, configurationMethod{name: name, method: m})
+	}
+	return methods, names, nil
+}
+
+// rollbackConfiguration 撤销配置类已注册的bean和值组成员，返回其中已初始化的单例bean，调用方需持有锁
+// 只有容器初始化后注册时才会在写入容器之后失败，此时所有名称都是新注册的
+func (c *containerImpl) rollbackConfiguration(names []string, providers []*constructorProvider) []*BeanDefinition {
+	var live []*BeanDefinition
+	for i := len(names) - 1; i >= 0; i-- {
+		bean, exists := c.beans[names[i]]
+		if !exists {
+			continue
+		}
+		c.removeBean(names[i], bean)
+		if bean.Scope == Singleton && bean.initialized {
+			live = append(live, bean)
+		}
+	}
+
+	for group, members := range c.groups {
+		var kept []*groupMember
+		for _, m := range members {
+			if !containsProvider(providers, m.provider) {
+				kept = append(kept, m)
+			}
+		}
+		c.groups[group] = kept
+	}
+
+	c.logger.Warn("配置类注册失败，已撤销注册", zap.Strings("beans", names))
+	return live
+}
+
+// containsProvider 判断切片中是否包含指定的构造函数
+func containsProvider(providers []*constructorProvider, p *constructorProvider) bool {
+	for _, provider := range providers {
+		if provider == p {
+			return true
+		}
+	}
+	return false
+}
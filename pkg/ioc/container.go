@@ -4,8 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strings"
 	"sync"
+	"unsafe"
 
 	"go.uber.org/zap"
 )
@@ -19,6 +19,9 @@ const (
 
 	// Prototype 表示多例模式，每次获取依赖时创建新实例
 	Prototype
+
+	// Keyed 表示按键多例模式，每个键对应一个实例，通过GetWithKey获取
+	Keyed
 )
 
 // 添加初始化阶段常量
@@ -56,11 +59,75 @@ type BeanDefinition struct {
 	// 工厂函数，用于创建对象实例
 	Factory func() (interface{}, error)
 
+	// 按键创建实例的工厂函数，仅用于Keyed作用域
+	KeyedFactory func(key string) (interface{}, error)
+
+	// 显式声明的方法注入，在字段注入之后、PostConstruct之前调用
+	InjectMethods []MethodInjection
+
+	// 是否为首选bean，按类型注入有多个候选时优先选择
+	Primary bool
+
+	// 限定符，按类型注入时可通过qualifier标签选择
+	Qualifiers []string
+
+	// 标签，用于描述和筛选bean
+	Labels map[string]string
+
+	// 显式绑定的接口，按这些接口查找时直接命中该bean
+	Interfaces []reflect.Type
+
+	// 是否延迟初始化，仅用于Singleton作用域
+	Lazy bool
+
+	// 声明依赖的其他bean，这些bean在该bean创建之前完成初始化
+	DependsOn []string
+
+	// 初始化方法名，在PostConstruct之后调用，方法签名为 func() 或 func() error
+	InitMethod string
+
+	// 销毁方法名，在Destroy之后调用，方法签名为 func() 或 func() error
+	DestroyMethod string
+
+	// 是否禁止覆盖该bean定义
+	NonOverridable bool
+
+	// 所属模块，不属于任何模块时为空
+	Module string
+
+	// 是否为模块的私有bean，私有bean只能被同一模块的bean注入
+	Private bool
+
+	// 注册位置，覆盖bean定义时记录在日志中
+	source string
+
+	// 是否通过注册选项显式指定了Lazy，未指定时使用容器的默认设置
+	lazySet bool
+
+	// 延迟初始化进行中时非nil，初始化结束后关闭其done
+	lazyInit *initFrame
+
+	// 通过构造函数注册的bean的创建函数，from为发起创建的初始化帧，存在时代替Factory使用
+	construct func(from *initFrame) (interface{}, error)
+
 	// 是否已完成依赖注入
 	injected bool
 
 	// 是否已执行PostConstruct
 	initialized bool
+
+	// Keyed作用域下按键缓存的实例
+	keyedInstances map[string]*keyedEntry
+
+	// FactoryBean生产的单例对象
+	factoryObject interface{}
+}
+
+// Cloner 接口由需要深拷贝的多例bean模板实现
+// 以Prototype注册的实例作为模板，每次获取时复制模板；默认浅拷贝，实现该接口后使用Clone的结果
+type Cloner interface {
+	// Clone 返回模板的副本，副本随后会被注入依赖并调用PostConstruct
+	Clone() (interface{}, error)
 }
 
 // InitializingBean 接口定义了对象初始化的方法
@@ -71,6 +138,9 @@ type InitializingBean interface {
 
 // Container 定义IoC容器的接口
 type Container interface {
+	// 使用注册选项注册依赖
+	RegisterWith(instanceOrFactory interface{}, opts ...RegisterOption) error
+
 	// 注册依赖到容器
 	Register(name string, instance interface{}, scope Scope) error
 
@@ -80,29 +150,114 @@ type Container interface {
 	// 按类型注册依赖,支持配置名字
 	RegisterTypeWithName(typeName string, name string, instance interface{}) error
 
+	// 按类型注册依赖,支持配置名字和作用域
+	RegisterTypeWithScope(typeName string, name string, instance interface{}, scope Scope) error
+
 	// 注册依赖工厂
 	RegisterFactory(name string, scope Scope, factory func() (interface{}, error)) error
 
+	// 注册构造函数，参数从容器中解析
+	RegisterConstructor(name string, scope Scope, constructor interface{}) error
+
+	// 注册配置类，配置类的bean方法注册为bean
+	RegisterConfiguration(configuration interface{}) error
+
+	// 注册辅助注入工厂，运行时参数由调用方传入，其余参数从容器中解析
+	// 不返回error的工厂函数在创建实例失败时panic
+	RegisterAssistedFactory(name string, factoryType interface{}, constructor interface{}) error
+
+	// 注册按键创建实例的工厂函数
+	RegisterKeyedFactory(name string, factory func(key string) (interface{}, error)) error
+
+	// 将bean标记为首选bean，只能在容器初始化前调用
+	SetPrimary(name string) error
+
+	// 为bean添加限定符，只能在容器初始化前调用
+	SetQualifiers(name string, qualifiers ...string) error
+
+	// 声明bean依赖的其他bean，只能在容器初始化前调用
+	SetDependsOn(name string, dependsOn ...string) error
+
+	// 为bean注册别名
+	RegisterAlias(alias string, target string) error
+
+	// 移除bean，单例bean会被销毁
+	Unregister(name string) error
+
+	// 在容器初始化前替换已注册bean的实例或工厂函数
+	Override(name string, instanceOrFactory interface{}) error
+
+	// 安装模块及其导入的模块
+	Install(module *Module) error
+
+	// 获取条件注册的求值结果
+	GetConditionReport() []ConditionOutcome
+
+	// 为已注册的bean声明方法注入，只能在容器初始化前调用
+	RegisterMethodInjection(name string, method string, dependencies ...string) error
+
 	// 获取依赖
 	Get(name string) interface{}
 
 	// 按类型获取依赖
 	GetByType(typeName string, name string) interface{}
 
+	// 安全地按类型获取依赖，返回错误而不是panic
+	GetByTypeSafe(typeName string, name string) (interface{}, error)
+
+	// 按反射类型查找唯一匹配的依赖
+	ResolveType(t reflect.Type) (interface{}, error)
+
+	// 按容器的命名策略返回类型对应的bean名称
+	BeanNameFor(t reflect.Type) string
+
 	// 安全地获取依赖，返回错误而不是panic
 	GetSafe(name string) (interface{}, error)
 
-	// 获取所有注册的bean
+	// 获取指定键对应的实例
+	GetWithKey(name string, key string) interface{}
+
+	// 安全地获取指定键对应的实例，返回错误而不是panic
+	GetWithKeySafe(name string, key string) (interface{}, error)
+
+	// 移除指定键对应的实例
+	EvictKey(name string, key string) error
+
+	// 移除所有键对应的实例
+	EvictAllKeys(name string) error
+
+	// 获取当前存活实例的所有键
+	GetKeys(name string) ([]string, error)
+
+	// 校验所有bean的依赖关系
+	Validate() error
+
+	// 导出bean的依赖关系图
+	DependencyGraph() *DependencyGraph
+
+	// 获取所有注册的bean，返回注册表的副本
 	GetAll() map[string]*BeanDefinition
 
 	// 获取所有注册的bean名称
 	GetAllNames() []string
 
+	// 获取所有别名及其指向的bean名称
+	GetAliases() map[string]string
+
 	// 注入依赖
 	Inject(instance interface{}) error
 
+	// 调用函数，参数从容器中解析
+	Invoke(fn interface{}) error
+
 	// 初始化容器
 	Init() error
+
+	// 启动容器，调用bean的Start方法
+	Start() error
+
+	// 关闭容器，调用bean的Destroy方法
+	Close() error
 }
 
 // 默认的容器实现
@@ -113,9 +268,57 @@ type containerImpl struct {
 	// 按类型分组的bean
 	typeRegistry map[string]map[string]*BeanDefinition
 
+	// 值组，组名到成员列表
+	groups map[string][]*groupMember
+
+	// 接口索引，接口类型到显式绑定了该接口的bean名称
+	typeIndex map[reflect.Type][]string
+
+	// 是否只通过显式绑定按接口查找bean
+	explicitBindings bool
+
+	// 单例bean默认是否延迟初始化
+	lazyInit bool
+
+	// 别名到目标名称的映射，目标可以是另一个别名
+	aliases map[string]string
+
+	// 通过别名获取bean时是否记录弃用警告
+	aliasWarnings bool
+
+	// 是否允许同名注册覆盖已有的bean定义
+	allowOverride bool
+
+	// 未指定名称时根据类型生成bean名称的策略
+	naming NamingStrategy
+
+	// 已安装的模块
+	modules map[string]*Module
+
+	// 容器属性，供OnProperty条件使用
+	properties map[string]string
+
+	// 等待初始化时求值条件的注册
+	pending []*pendingRegistration
+
+	// 条件注册的求值结果
+	conditionOutcomes []ConditionOutcome
+
+	// 单例bean完成初始化的顺序，Start按此顺序执行，Close按逆序执行
+	initOrder []string
+
+	// 容器是否已启动
+	started bool
+
+	// 容器是否已关闭
+	closed bool
+
 	// 保护并发访问的互斥锁
 	mu sync.RWMutex
 
+	// 保护初始化帧之间等待关系的互斥锁
+	frameMu sync.Mutex
+
 	// 标记容器是否已初始化
 	initialized bool
 
@@ -127,210 +330,96 @@ type containerImpl struct {
 
 	// 日志记录器
 	logger Logger
+
+	// 是否允许向带有inject标签的未导出字段注入
+	allowUnexportedInjection bool
+}
+
+// ContainerOption 容器配置选项
+type ContainerOption func(*containerImpl)
+
+// WithUnexportedFieldInjection 允许向带有inject标签的未导出字段注入依赖，默认关闭
+// 仅用于迁移无法添加setter方法的旧代码，每次注入未导出字段都会记录警告日志
+func WithUnexportedFieldInjection(enabled bool) ContainerOption {
+	return func(c *containerImpl) {
+		c.allowUnexportedInjection = enabled
+	}
+}
+
+// WithExplicitBindings 只通过As显式绑定按接口查找bean，默认关闭
+// 开启后未绑定某接口的bean即使实现了该接口也不会被按接口自动注入，避免意外匹配
+func WithExplicitBindings(enabled bool) ContainerOption {
+	return func(c *containerImpl) {
+		c.explicitBindings = enabled
+	}
+}
+
+// WithLazyInit 设置单例bean默认是否延迟初始化，默认关闭
+// 可以通过注册选项Lazy为单个bean覆盖该设置
+func WithLazyInit(enabled bool) ContainerOption {
+	return func(c *containerImpl) {
+		c.lazyInit = enabled
+	}
 }
 
 // 创建新的容器实例
-func NewContainer() Container {
+func NewContainer(opts ...ContainerOption) Container {
 	// 确保日志系统已初始化
 	logger := GetLogger()
 
 	logger.Debug("创建新的IoC容器实例")
 
-	return &containerImpl{
+	c := &containerImpl{
 		beans:        make(map[string]*BeanDefinition),
 		typeRegistry: make(map[string]map[string]*BeanDefinition),
+		groups:       make(map[string][]*groupMember),
+		typeIndex:    make(map[reflect.Type][]string),
+		aliases:      make(map[string]string),
+		modules:      make(map[string]*Module),
+		properties:   make(map[string]string),
 		initializing: make(map[string]bool),
 		currentPhase: NotInitialized,
 		logger:       logger,
+		naming:       ShortTypeName,
 	}
-}
 
-// Register 注册一个依赖到容器
-func (c *containerImpl) Register(name string, instance interface{}, scope Scope) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.initialized {
-		c.logger.Error("容器已初始化，无法注册新的bean",
-			zap.String("beanName", name))
-		return errors.New("cannot register beans after container initialization")
+	for _, opt := range opts {
+		opt(c)
 	}
 
-	if instance == nil {
-		c.logger.Error("无法注册空实例",
-			zap.String("beanName", name))
-		return errors.New("cannot register nil instance")
-	}
-
-	// 获取实例的类型
-	t := reflect.TypeOf(instance)
-
-	// 创建bean定义
-	bean := &BeanDefinition{
-		Name:     name,
-		Type:     t,
-		Instance: instance,
-		Scope:    scope,
-	}
-
-	// 检查是否已存在同名bean
-	if _, exists := c.beans[name]; exists {
-		c.logger.Error("bean名称已存在",
-			zap.String("beanName", name))
-		return fmt.Errorf("bean with name '%s' already exists", name)
-	}
+	return c
+}
 
-	c.beans[name] = bean
-	c.logger.Debug("成功注册bean",
-		zap.String("beanName", name),
-		zap.String("type", t.String()),
-		zap.Int("scope", scope))
-	return nil
+// Register 注册一个依赖到容器
+func (c *containerImpl) Register(name string, instance interface{}, scope Scope) error {
+	return c.RegisterWith(instance, Name(name), InScope(scope))
 }
 
 // RegisterType 按类型注册依赖
 func (c *containerImpl) RegisterType(typeName string, instance interface{}) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.initialized {
-		return errors.New("cannot register beans after container initialization")
-	}
-
-	if instance == nil {
-		return errors.New("cannot register nil instance")
-	}
-
-	// 获取实例的类型
-	t := reflect.TypeOf(instance)
-
-	// 获取类型名
-	fullTypeName := t.String()
-
-	// 如果是指针，获取指向的元素类型
-	if t.Kind() == reflect.Ptr {
-		fullTypeName = t.Elem().String()
-	}
-
-	// 提取简短类型名（不含包路径）
-	parts := strings.Split(fullTypeName, ".")
-	shortTypeName := parts[len(parts)-1]
-
-	// 为确保唯一性，生成bean名称
-	beanName := shortTypeName
-	if typeName != "" {
-		beanName = typeName + ":" + shortTypeName
-	}
-
-	// 创建bean定义
-	bean := &BeanDefinition{
-		Name:     beanName,
-		TypeName: typeName,
-		Type:     t,
-		Instance: instance,
-		Scope:    Singleton, // 默认为单例
-	}
-
-	// 检查是否已存在同名bean
-	if _, exists := c.beans[beanName]; exists {
-		return fmt.Errorf("bean with name '%s' already exists", beanName)
-	}
-
-	// 注册到总表
-	c.beans[beanName] = bean
-
-	// 注册到类型表
-	if _, exists := c.typeRegistry[typeName]; !exists {
-		c.typeRegistry[typeName] = make(map[string]*BeanDefinition)
-	}
-	c.typeRegistry[typeName][shortTypeName] = bean
-
-	return nil
+	return c.RegisterWith(instance, TypeName(typeName))
 }
 
 // RegisterTypeWithName 按类型注册依赖,支持配置名字
 func (c *containerImpl) RegisterTypeWithName(typeName string, name string, instance interface{}) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.initialized {
-		return errors.New("cannot register beans after container initialization")
-	}
-
-	if instance == nil {
-		return errors.New("cannot register nil instance")
-	}
-
-	// 获取实例的类型
-	t := reflect.TypeOf(instance)
-
-	// 如果是指针，获取指向的元素类型
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
+	if name == "" {
+		return errors.New("bean name cannot be empty")
 	}
+	return c.RegisterWith(instance, TypeName(typeName), Name(name))
+}
 
-	// 为确保唯一性，生成bean名称
-	beanName := typeName + ":" + name
-
-	// 创建bean定义
-	bean := &BeanDefinition{
-		Name:     beanName,
-		TypeName: typeName,
-		Type:     t,
-		Instance: instance,
-		Scope:    Singleton, // 默认为单例
-	}
-
-	// 检查是否已存在同名bean
-	if _, exists := c.beans[beanName]; exists {
-		return fmt.Errorf("bean with name '%s' already exists", beanName)
-	}
-
-	// 注册到总表
-	c.beans[beanName] = bean
-
-	// 注册到类型表
-	if _, exists := c.typeRegistry[typeName]; !exists {
-		c.typeRegistry[typeName] = make(map[string]*BeanDefinition)
-	}
-
-	// 检查是否已存在同名bean
-	if _, exists := c.typeRegistry[typeName][name]; exists {
-		return fmt.Errorf("type '%s' already has a bean with name '%s'", typeName, name)
-	}
-
-	c.typeRegistry[typeName][name] = bean
-
-	return nil
+// RegisterTypeWithScope 按类型注册依赖,支持配置名字和作用域
+// name为空时按命名策略生成，bean名称为 typeName:name
+func (c *containerImpl) RegisterTypeWithScope(typeName string, name string, instance interface{}, scope Scope) error {
+	return c.RegisterWith(instance, TypeName(typeName), Name(name), InScope(scope))
 }
 
 // RegisterFactory 注册一个工厂函数用于创建bean实例
 func (c *containerImpl) RegisterFactory(name string, scope Scope, factory func() (interface{}, error)) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	if c.initialized {
-		return errors.New("cannot register beans after container initialization")
-	}
-
 	if factory == nil {
 		return errors.New("factory function cannot be nil")
 	}
-
-	// 创建bean定义
-	bean := &BeanDefinition{
-		Name:    name,
-		Scope:   scope,
-		Factory: factory,
-	}
-
-	// 检查是否已存在同名bean
-	if _, exists := c.beans[name]; exists {
-		return fmt.Errorf("bean with name '%s' already exists", name)
-	}
-
-	c.beans[name] = bean
-	return nil
+	return c.RegisterWith(factory, Name(name), InScope(scope))
 }
 
 // Get 获取依赖
@@ -347,8 +436,37 @@ func (c *containerImpl) Get(name string) interface{} {
 }
 
 // GetSafe 安全地获取依赖，返回错误而不是panic
+// 模块的私有bean不能通过GetSafe获取
 func (c *containerImpl) GetSafe(name string) (interface{}, error) {
 	c.mu.RLock()
+	_, err := c.visibleName("", name)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getSafe(name)
+}
+
+// getSafe 按名称获取依赖，不检查模块可见性
+func (c *containerImpl) getSafe(name string) (interface{}, error) {
+	// 带有前缀的名称返回FactoryBean本身
+	if factoryName, ok := trimFactoryBeanPrefix(name); ok {
+		return c.getFactoryBean(c.resolveAlias(factoryName), c.getBean)
+	}
+
+	name = c.resolveAlias(name)
+
+	instance, err := c.getBean(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.unwrapFactoryBean(name, instance, nil)
+}
+
+// getBean 获取bean实例，不处理FactoryBean
+func (c *containerImpl) getBean(name string) (interface{}, error) {
+	c.mu.RLock()
 
 	// 获取bean定义
 	bean, exists := c.beans[name]
@@ -358,6 +476,12 @@ func (c *containerImpl) GetSafe(name string) (interface{}, error) {
 		return nil, fmt.Errorf("bean with name '%s' not found", name)
 	}
 
+	// Keyed作用域的bean必须指定键
+	if bean.Scope == Keyed {
+		c.mu.RUnlock()
+		return nil, fmt.Errorf("bean '%s' is keyed, use GetWithKey instead", name)
+	}
+
 	// 根据当前阶段进行不同处理
 	switch c.currentPhase {
 	case NotInitialized:
@@ -369,9 +493,9 @@ func (c *containerImpl) GetSafe(name string) (interface{}, error) {
 	case InjectionPhase:
 		// 注入阶段 - 只返回已存在的实例
 		if bean.Instance == nil {
-			c.mu.RUnlock()
 			// 如果在注入阶段获取尚未初始化的bean，可能导致循环依赖
 			if c.initializing[name] {
+				c.mu.RUnlock()
 				c.logger.Error("检测到循环依赖", zap.String("beanName", name))
 				return nil, fmt.Errorf("circular dependency detected for bean: %s", name)
 			}
@@ -382,13 +506,13 @@ func (c *containerImpl) GetSafe(name string) (interface{}, error) {
 			c.mu.Lock()
 			defer c.mu.Unlock()
 
-			if err := c.createBeanInstance(name, bean); err != nil {
+			if err := c.createBeanInstance(name, bean, nil); err != nil {
 				c.logger.Error("创建bean实例失败",
 					zap.String("beanName", name),
 					zap.Error(err))
 				return nil, err
 			}
-			if err := c.injectBeanDependencies(name, bean); err != nil {
+			if err := c.injectBeanDependencies(name, bean, nil); err != nil {
 				c.logger.Error("注入bean依赖失败",
 					zap.String("beanName", name),
 					zap.Error(err))
@@ -405,6 +529,14 @@ func (c *containerImpl) GetSafe(name string) (interface{}, error) {
 	case PostConstructPhase, Initialized:
 		// PostConstruct阶段或已初始化 - 正常返回实例
 		if bean.Scope == Singleton {
+			// 延迟初始化的bean在首次获取时初始化，初始化后注册的bean等待其初始化完成
+			if !bean.initialized && (bean.Lazy || bean.lazyInit != nil) {
+				c.mu.RUnlock()
+				if err := c.initializeLazyBean(name, bean, nil); err != nil {
+					return nil, err
+				}
+				c.mu.RLock()
+			}
 			instance := bean.Instance
 			c.mu.RUnlock()
 			return instance, nil
@@ -412,7 +544,7 @@ func (c *containerImpl) GetSafe(name string) (interface{}, error) {
 
 		// 对于Prototype，创建新实例
 		c.mu.RUnlock()
-		return c.createPrototypeInstance(bean)
+		return c.createPrototypeInstance(bean, nil)
 	}
 
 	c.mu.RUnlock()
@@ -422,75 +554,158 @@ func (c *containerImpl) GetSafe(name string) (interface{}, error) {
 	return nil, errors.New("unknown container state")
 }
 
-// 创建Prototype类型的bean实例
-func (c *containerImpl) createPrototypeInstance(bean *BeanDefinition) (interface{}, error) {
+// 创建Prototype类型的bean实例，from为发起创建的初始化帧
+func (c *containerImpl) createPrototypeInstance(bean *BeanDefinition, from *initFrame) (interface{}, error) {
 	var instance interface{}
 	var err error
 
-	// 使用工厂函数或创建新实例
-	if bean.Factory != nil {
+	// 使用构造函数、工厂函数或创建新实例
+	if bean.construct != nil {
+		instance, err = bean.construct(from)
+		if err != nil {
+			return nil, err
+		}
+	} else if bean.Factory != nil {
 		instance, err = bean.Factory()
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		instance = reflect.New(bean.Type.Elem()).Interface()
+		instance, err = newPrototypeInstance(bean)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// 注入依赖
-	if err := c.Inject(instance); err != nil {
+	if err := c.initializePrototype(instance, bean, from); err != nil {
 		return nil, err
 	}
 
+	return instance, nil
+}
+
+// newPrototypeInstance 在没有工厂函数时以注册的实例为模板创建新的多例实例
+// 模板实现了Cloner接口时使用其Clone方法深拷贝；指向结构体的指针浅拷贝其指向的结构体；
+// 值类型（结构体值、数字、字符串等）返回注册实例的副本
+func newPrototypeInstance(bean *BeanDefinition) (interface{}, error) {
+	if bean.Instance == nil {
+		return nil, fmt.Errorf("prototype bean '%s' has neither instance nor factory", bean.Name)
+	}
+
+	if cloner, ok := bean.Instance.(Cloner); ok {
+		instance, err := cloner.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("error cloning prototype bean '%s': %w", bean.Name, err)
+		}
+		if instance == nil {
+			return nil, fmt.Errorf("prototype bean '%s' cloned to nil instance", bean.Name)
+		}
+		return instance, nil
+	}
+
+	t := reflect.TypeOf(bean.Instance)
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct {
+		// 浅拷贝：复制所有字段，引用类型的字段与模板共享
+		instance := reflect.New(t.Elem())
+		instance.Elem().Set(reflect.ValueOf(bean.Instance).Elem())
+		return instance.Interface(), nil
+	}
+
+	if requiresFactory(t) {
+		return nil, fmt.Errorf("prototype bean '%s' of kind %s requires a factory", bean.Name, t.Kind())
+	}
+
+	// 接口中保存的值类型在赋值时即被复制
+	return bean.Instance, nil
+}
+
+// requiresFactory 判断该类型的多例bean是否必须通过工厂函数创建
+// 引用类型的实例无法通过复制得到相互独立的新实例
+func requiresFactory(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return t.Elem().Kind() != reflect.Struct
+	case reflect.Func, reflect.Map, reflect.Chan, reflect.Slice, reflect.Interface, reflect.UnsafePointer:
+		return true
+	default:
+		return false
+	}
+}
+
+// initializePrototype 为新创建的多例实例注入依赖并调用PostConstruct，from为发起创建的初始化帧
+func (c *containerImpl) initializePrototype(instance interface{}, bean *BeanDefinition, from *initFrame) error {
+	// 注入依赖
+	if err := c.injectInto(instance, bean, c.currentResolver(from)); err != nil {
+		return err
+	}
+
 	// 调用初始化方法
 	if initializer, ok := instance.(InitializingBean); ok {
 		if err := initializer.PostConstruct(); err != nil {
-			return nil, err
+			return err
 		}
 	}
+	if bean != nil && bean.InitMethod != "" {
+		return callLifecycleMethod(instance, bean.InitMethod)
+	}
 
-	return instance, nil
+	return nil
 }
 
 // GetByType 按类型获取依赖
 func (c *containerImpl) GetByType(typeName string, name string) interface{} {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	// 检查容器是否已初始化
-	if c.currentPhase == NotInitialized {
-		panic("container not initialized, call Init() first")
+	instance, err := c.GetByTypeSafe(typeName, name)
+	if err != nil {
+		c.logger.Error("按类型获取bean失败",
+			zap.String("typeName", typeName),
+			zap.String("beanName", name),
+			zap.Error(err))
+		panic(err)
 	}
+	return instance
+}
+
+// GetByTypeSafe 安全地按类型获取依赖，返回错误而不是panic
+// 与GetSafe使用相同的解析流程，多例bean同样会完成依赖注入和PostConstruct
+func (c *containerImpl) GetByTypeSafe(typeName string, name string) (interface{}, error) {
+	c.mu.RLock()
 
 	// 获取类型注册表
 	typeMap, exists := c.typeRegistry[typeName]
 	if !exists {
-		panic(fmt.Sprintf("no beans registered for type '%s'", typeName))
+		c.mu.RUnlock()
+		return nil, fmt.Errorf("no beans registered for type '%s'", typeName)
 	}
 
 	// 获取特定名称的bean
 	bean, exists := typeMap[name]
 	if !exists {
-		panic(fmt.Sprintf("bean with name '%s' of type '%s' not found", name, typeName))
-	}
-
-	// 单例直接返回实例
-	if bean.Scope == Singleton {
-		return bean.Instance
+		c.mu.RUnlock()
+		return nil, fmt.Errorf("bean with name '%s' of type '%s' not found", name, typeName)
 	}
+	beanName := bean.Name
+	c.mu.RUnlock()
 
-	// 多例创建新实例（简化处理，完整实现应该像Get方法一样）
-	newInstance := reflect.New(bean.Type.Elem()).Interface()
-	return newInstance
+	return c.GetSafe(beanName)
 }
 
-// GetAll 获取所有注册的bean
+// GetAll 获取所有注册的bean，返回的是注册表的副本
 func (c *containerImpl) GetAll() map[string]*BeanDefinition {
-	return c.beans
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	beans := make(map[string]*BeanDefinition, len(c.beans))
+	for name, bean := range c.beans {
+		beans[name] = bean
+	}
+	return beans
 }
 
 // GetAllNames 获取所有注册的bean名称
 func (c *containerImpl) GetAllNames() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	names := make([]string, 0, len(c.beans))
 	for name := range c.beans {
 		names = append(names, name)
@@ -500,10 +715,55 @@ func (c *containerImpl) GetAllNames() []string {
 
 // Inject 注入依赖
 func (c *containerImpl) Inject(instance interface{}) error {
+	return c.injectInto(instance, nil, c.runtimeResolver())
+}
+
+// dependencyResolver 封装依赖的查找方式，初始化过程中与初始化完成后的查找策略不同
+type dependencyResolver struct {
+	// 按名称查找bean
+	byName func(name string) (interface{}, error)
+
+	// 按类型查找bean，qualifier非空时只匹配带有该限定符的bean
+	byType func(t reflect.Type, qualifier string) (interface{}, error)
+
+	// 发起查找的初始化帧，不属于任何初始化过程时为nil
+	frame *initFrame
+}
+
+// 初始化完成后使用的查找方式
+func (c *containerImpl) runtimeResolver() *dependencyResolver {
+	return &dependencyResolver{
+		byName: c.getSafe,
+		byType: c.findCandidateByType,
+	}
+}
+
+// 初始化过程中使用的查找方式，from为发起查找的初始化帧
+func (c *containerImpl) initResolver(from *initFrame) *dependencyResolver {
+	return &dependencyResolver{
+		byName: func(name string) (interface{}, error) {
+			return c.getBeanForInit(name, from)
+		},
+		byType: func(t reflect.Type, qualifier string) (interface{}, error) {
+			return c.findCandidateByTypeForInit(t, qualifier, from)
+		},
+		frame: from,
+	}
+}
+
+// injectInto 向实例注入依赖，bean为nil表示实例不是容器管理的bean
+func (c *containerImpl) injectInto(instance interface{}, bean *BeanDefinition, r *dependencyResolver) error {
 	if instance == nil {
 		return errors.New("cannot inject into nil instance")
 	}
 
+	// 按bean所属模块的可见性查找依赖
+	module := ""
+	if bean != nil {
+		module = bean.Module
+	}
+	r = c.scopedResolver(module, r)
+
 	val := reflect.ValueOf(instance)
 
 	// 如果是指针，获取其元素
@@ -511,11 +771,49 @@ func (c *containerImpl) Inject(instance interface{}) error {
 		val = val.Elem()
 	}
 
-	// 只能向结构体注入
+	// 只能向结构体注入，非结构体的bean（函数、map、基本类型等）跳过注入
 	if val.Kind() != reflect.Struct {
+		if bean != nil {
+			c.logger.Debug("bean不是结构体，跳过注入",
+				zap.String("beanName", bean.Name),
+				zap.String("kind", val.Kind().String()))
+			return nil
+		}
 		return fmt.Errorf("can only inject into struct, got %s", val.Kind())
 	}
 
+	// 结构体值无法被修改，只有在没有需要注入的字段时才允许
+	if !val.CanAddr() {
+		if hasInjectFields(val.Type()) {
+			return fmt.Errorf("cannot inject into struct value %s, use a pointer instead", val.Type())
+		}
+		return nil
+	}
+
+	// 字段注入
+	if err := c.injectFields(val, r); err != nil {
+		return err
+	}
+
+	// 回调Aware接口
+	c.invokeAware(instance, bean)
+
+	// 方法注入在字段注入之后、PostConstruct之前执行
+	return c.injectMethods(instance, bean, r)
+}
+
+// hasInjectFields 判断结构体是否有带inject标签的字段
+func hasInjectFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("inject"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// injectFields 为带有inject标签的字段注入依赖
+func (c *containerImpl) injectFields(val reflect.Value, r *dependencyResolver) error {
 	// 获取类型
 	t := val.Type()
 
@@ -523,9 +821,9 @@ func (c *containerImpl) Inject(instance interface{}) error {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 
-		// 检查是否有注入标签
-		injectTag := field.Tag.Get("inject")
-		if injectTag == "" {
+		// 检查是否有注入标签，inject:"" 表示按类型注入
+		injectTag, ok := field.Tag.Lookup("inject")
+		if !ok {
 			continue
 		}
 
@@ -538,11 +836,11 @@ func (c *containerImpl) Inject(instance interface{}) error {
 		var err error
 
 		if injectTag == "" {
-			// 自动查找匹配类型的bean
-			bean, err = c.findCandidateByType(field.Type)
+			// 自动查找匹配类型的bean，可通过qualifier标签缩小候选范围
+			bean, err = r.byType(field.Type, field.Tag.Get("qualifier"))
 		} else {
 			// 根据名称获取bean
-			bean, err = c.GetSafe(injectTag)
+			bean, err = r.byName(injectTag)
 		}
 
 		// 处理查找/获取错误
@@ -555,9 +853,9 @@ func (c *containerImpl) Inject(instance interface{}) error {
 		}
 
 		// 设置字段值
-		fieldVal := val.Field(i)
-		if !fieldVal.CanSet() {
-			return fmt.Errorf("cannot set field '%s', it might be unexported", field.Name)
+		fieldVal, err := c.settableField(val, field, i)
+		if err != nil {
+			return err
 		}
 
 		beanVal := reflect.ValueOf(bean)
@@ -573,35 +871,113 @@ func (c *containerImpl) Inject(instance interface{}) error {
 	return nil
 }
 
+// settableField 返回可设置的字段值，启用相应选项时允许设置未导出字段
+func (c *containerImpl) settableField(val reflect.Value, field reflect.StructField, i int) (reflect.Value, error) {
+	fieldVal := val.Field(i)
+	if fieldVal.CanSet() {
+		return fieldVal, nil
+	}
+
+	if !c.allowUnexportedInjection || field.IsExported() || !fieldVal.CanAddr() {
+		return reflect.Value{}, fmt.Errorf("cannot set field '%s', it might be unexported", field.Name)
+	}
+
+	c.logger.Warn("向未导出字段注入依赖",
+		zap.String("type", val.Type().String()),
+		zap.String("field", field.Name))
+	return reflect.NewAt(field.Type, unsafe.Pointer(fieldVal.UnsafeAddr())).Elem(), nil
+}
+
 // 查找匹配类型的bean候选
-func (c *containerImpl) findCandidateByType(t reflect.Type) (interface{}, error) {
-	var candidates []string
+func (c *containerImpl) findCandidateByType(t reflect.Type, qualifier string) (interface{}, error) {
+	// 声明为Container类型的依赖注入所属容器
+	if t == containerType {
+		return c, nil
+	}
 
-	for name, bean := range c.beans {
-		beanType := bean.Type
+	c.mu.RLock()
+	name, err := c.selectCandidate(t, qualifier, c.matchCandidates(t))
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
 
-		// 检查类型匹配
-		if t.Kind() == reflect.Interface {
-			// 如果要注入的是接口，检查bean是否实现了该接口
-			if beanType.Implements(t) {
+	// 获取唯一的候选bean
+	return c.getSafe(name)
+}
+
+// matchCandidates 返回类型与t匹配的bean名称，包括显式绑定了接口t的bean，调用方需持有锁
+// 模块的私有bean只对所属模块可见，不参与匹配
+func (c *containerImpl) matchCandidates(t reflect.Type) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+
+	// 显式绑定的bean可能是类型尚未确定的工厂bean，需单独加入
+	if t.Kind() == reflect.Interface {
+		for _, name := range c.typeIndex[t] {
+			if !c.beans[name].Private {
 				candidates = append(candidates, name)
+				seen[name] = true
 			}
-		} else if t == beanType {
-			// 直接的类型匹配
+		}
+	}
+
+	for name, bean := range c.beans {
+		if !seen[name] && !bean.Private && c.typeMatches(bean, t) {
 			candidates = append(candidates, name)
 		}
 	}
 
-	if len(candidates) == 0 {
-		return nil, fmt.Errorf("no bean candidate found for type %s", t)
+	return candidates
+}
+
+// boundCandidates 返回候选中显式绑定了接口t的bean，没有绑定的候选时原样返回，调用方需持有锁
+func (c *containerImpl) boundCandidates(t reflect.Type, candidates []string) []string {
+	if t.Kind() != reflect.Interface {
+		return candidates
+	}
+	var bound []string
+	for _, name := range candidates {
+		if containsString(c.typeIndex[t], name) {
+			bound = append(bound, name)
+		}
+	}
+	if len(bound) == 0 {
+		return candidates
 	}
+	return bound
+}
+
+// typeMatches 判断bean的类型是否与t匹配，调用方需持有锁
+func (c *containerImpl) typeMatches(bean *BeanDefinition, t reflect.Type) bool {
+	beanType := bean.Type
 
-	if len(candidates) > 1 {
-		return nil, fmt.Errorf("multiple bean candidates found for type %s: %v", t, candidates)
+	// FactoryBean按其生产的对象类型匹配
+	if factory, ok := bean.Instance.(FactoryBean); ok {
+		beanType = factory.ObjectType()
 	}
 
-	// 获取唯一的候选bean
-	return c.Get(candidates[0]), nil
+	if beanType == nil {
+		return false // 跳过类型尚未确定的工厂bean
+	}
+
+	// 检查类型匹配
+	if t.Kind() == reflect.Interface {
+		// 只允许显式绑定时，未绑定接口的bean不参与按接口匹配
+		if c.explicitBindings {
+			for _, iface := range bean.Interfaces {
+				if iface == t {
+					return true
+				}
+			}
+			return false
+		}
+		// 如果要注入的是接口，检查bean是否实现了该接口
+		return beanType.Implements(t)
+	}
+
+	// 直接的类型匹配
+	return t == beanType
 }
 
 // Init 实现两阶段初始化
@@ -616,6 +992,40 @@ func (c *containerImpl) Init() error {
 
 	c.logger.Info("开始初始化IoC容器", zap.Int("beanCount", len(c.beans)))
 
+	// 求值条件注册
+	if err := c.registerPending(); err != nil {
+		c.logger.Error("条件注册bean失败", zap.Error(err))
+		return err
+	}
+
+	// 检查别名和DependsOn声明
+	if err := c.checkAliases(); err != nil {
+		c.logger.Error("bean别名校验失败", zap.Error(err))
+		return err
+	}
+	if err := c.checkDependsOn(); err != nil {
+		c.logger.Error("bean依赖声明校验失败", zap.Error(err))
+		return err
+	}
+
+	// 应用延迟初始化的默认设置，延迟初始化的bean在启动时只校验依赖关系
+	var lazyErrs []error
+	for name, bean := range c.beans {
+		if bean.Scope != Singleton {
+			continue
+		}
+		if !bean.lazySet {
+			bean.Lazy = c.lazyInit
+		}
+		if bean.Lazy && !bean.initialized {
+			lazyErrs = append(lazyErrs, c.validateBean(name, bean)...)
+		}
+	}
+	if err := errors.Join(lazyErrs...); err != nil {
+		c.logger.Error("延迟初始化bean的依赖校验失败", zap.Error(err))
+		return err
+	}
+
 	// 第一阶段：依赖注入
 	c.logger.Info("进入第一阶段：依赖注入")
 	c.currentPhase = InjectionPhase
@@ -623,8 +1033,8 @@ func (c *containerImpl) Init() error {
 	// 先为所有单例bean创建实例
 	c.logger.Debug("开始创建所有单例bean实例")
 	for name, bean := range c.beans {
-		if bean.Scope == Singleton {
-			if err := c.createBeanInstance(name, bean); err != nil {
+		if bean.Scope == Singleton && !bean.Lazy {
+			if err := c.createBeanInstance(name, bean, nil); err != nil {
 				c.logger.Error("创建bean实例失败",
 					zap.String("beanName", name),
 					zap.Error(err))
@@ -636,8 +1046,8 @@ func (c *containerImpl) Init() error {
 	// 然后为所有单例bean注入依赖
 	c.logger.Debug("开始为所有单例bean注入依赖")
 	for name, bean := range c.beans {
-		if bean.Scope == Singleton {
-			if err := c.injectBeanDependencies(name, bean); err != nil {
+		if bean.Scope == Singleton && !bean.Lazy {
+			if err := c.injectBeanDependencies(name, bean, nil); err != nil {
 				c.logger.Error("注入bean依赖失败",
 					zap.String("beanName", name),
 					zap.Error(err))
@@ -650,8 +1060,8 @@ func (c *containerImpl) Init() error {
 	c.logger.Info("进入第二阶段：初始化")
 	c.currentPhase = PostConstructPhase
 	for name, bean := range c.beans {
-		if bean.Scope == Singleton {
-			if err := c.initializeBean(name, bean); err != nil {
+		if bean.Scope == Singleton && !bean.Lazy {
+			if err := c.initializeBean(name, bean, nil); err != nil {
 				c.logger.Error("初始化bean失败",
 					zap.String("beanName", name),
 					zap.Error(err))
@@ -668,8 +1078,13 @@ func (c *containerImpl) Init() error {
 	return nil
 }
 
-// 创建bean实例
-func (c *containerImpl) createBeanInstance(name string, bean *BeanDefinition) error {
+// 创建bean实例，from为发起创建的初始化帧
+func (c *containerImpl) createBeanInstance(name string, bean *BeanDefinition, from *initFrame) error {
+	// 先初始化声明依赖的bean
+	if err := c.ensureDependsOn(name, bean, from); err != nil {
+		return err
+	}
+
 	// 如果已经有实例，跳过
 	if bean.Instance != nil {
 		c.logger.Debug("bean实例已存在，跳过创建",
@@ -689,16 +1104,38 @@ func (c *containerImpl) createBeanInstance(name string, bean *BeanDefinition) er
 	c.initializing[name] = true
 	defer delete(c.initializing, name)
 
-	// 如果是工厂方法，调用它创建实例
-	if bean.Factory != nil {
+	// 如果是构造函数或工厂方法，调用它创建实例
+	if bean.construct != nil || bean.Factory != nil {
 		c.logger.Debug("使用工厂方法创建bean实例",
 			zap.String("beanName", name))
-		instance, err := bean.Factory()
+
+		// 临时释放锁，工厂函数可能需要从容器中解析依赖
+		c.mu.Unlock()
+		var instance interface{}
+		var err error
+		if bean.construct != nil {
+			instance, err = bean.construct(from)
+		} else {
+			instance, err = bean.Factory()
+		}
+		c.mu.Lock()
+
 		if err != nil {
 			c.logger.Error("工厂方法创建实例失败",
 				zap.String("beanName", name),
 				zap.Error(err))
-			return fmt.Errorf("error creating instance for bean '%s': %w", name, err)
+			return fmt.Errorf("error creating instance for bean '%s': %w", c.displayName(name), err)
+		}
+		if instance == nil {
+			return fmt.Errorf("factory for bean '%s' returned nil instance", name)
+		}
+		for _, iface := range bean.Interfaces {
+			if !reflect.TypeOf(instance).Implements(iface) {
+				return fmt.Errorf("bean '%s' of type %T does not implement interface %s", name, instance, iface)
+			}
+		}
+		if err := validateLifecycleMethods(reflect.TypeOf(instance), bean); err != nil {
+			return fmt.Errorf("bean '%s': %w", name, err)
 		}
 		bean.Instance = instance
 
@@ -713,8 +1150,8 @@ func (c *containerImpl) createBeanInstance(name string, bean *BeanDefinition) er
 	return nil
 }
 
-// 注入bean依赖
-func (c *containerImpl) injectBeanDependencies(name string, bean *BeanDefinition) error {
+// 注入bean依赖，from为发起注入的初始化帧
+func (c *containerImpl) injectBeanDependencies(name string, bean *BeanDefinition, from *initFrame) error {
 	// 如果已经注入过，跳过
 	if bean.injected {
 		c.logger.Debug("bean已注入依赖，跳过注入",
@@ -729,7 +1166,7 @@ func (c *containerImpl) injectBeanDependencies(name string, bean *BeanDefinition
 	c.mu.Unlock()
 
 	// 注入依赖
-	err := c.injectDuringInit(bean.Instance)
+	err := c.injectDuringInit(bean.Instance, bean, from)
 
 	// 重新获取锁
 	c.mu.Lock()
@@ -738,7 +1175,7 @@ func (c *containerImpl) injectBeanDependencies(name string, bean *BeanDefinition
 		c.logger.Error("注入依赖失败",
 			zap.String("beanName", name),
 			zap.Error(err))
-		return fmt.Errorf("error injecting dependencies for bean '%s': %w", name, err)
+		return fmt.Errorf("error injecting dependencies for bean '%s': %w", c.displayName(name), err)
 	}
 
 	// 标记为已注入
@@ -748,8 +1185,8 @@ func (c *containerImpl) injectBeanDependencies(name string, bean *BeanDefinition
 	return nil
 }
 
-// 初始化bean
-func (c *containerImpl) initializeBean(name string, bean *BeanDefinition) error {
+// 初始化bean，from为发起初始化的初始化帧
+func (c *containerImpl) initializeBean(name string, bean *BeanDefinition, from *initFrame) error {
 	// 如果已经初始化过，跳过
 	if bean.initialized {
 		c.logger.Debug("bean已初始化，跳过",
@@ -761,18 +1198,19 @@ func (c *containerImpl) initializeBean(name string, bean *BeanDefinition) error
 	if !bean.injected {
 		c.logger.Debug("bean尚未注入依赖，先注入依赖",
 			zap.String("beanName", name))
-		if err := c.injectBeanDependencies(name, bean); err != nil {
+		if err := c.injectBeanDependencies(name, bean, from); err != nil {
 			return err
 		}
 	}
 
-	// 检查是否实现了InitializingBean接口
+	// 检查是否实现了InitializingBean接口或声明了初始化方法
 	initializer, ok := bean.Instance.(InitializingBean)
-	if !ok {
+	if !ok && bean.InitMethod == "" {
 		// 未实现接口，标记为已初始化并返回
 		c.logger.Debug("bean未实现InitializingBean接口，跳过PostConstruct",
 			zap.String("beanName", name))
 		bean.initialized = true
+		c.initOrder = append(c.initOrder, name)
 		return nil
 	}
 
@@ -782,8 +1220,14 @@ func (c *containerImpl) initializeBean(name string, bean *BeanDefinition) error
 	// 临时释放锁，避免在PostConstruct过程中发生死锁
 	c.mu.Unlock()
 
-	// 调用PostConstruct方法
-	err := initializer.PostConstruct()
+	// 先调用PostConstruct方法，再调用声明的初始化方法
+	var err error
+	if ok {
+		err = initializer.PostConstruct()
+	}
+	if err == nil && bean.InitMethod != "" {
+		err = callLifecycleMethod(bean.Instance, bean.InitMethod)
+	}
 
 	// 重新获取锁
 	c.mu.Lock()
@@ -792,149 +1236,97 @@ func (c *containerImpl) initializeBean(name string, bean *BeanDefinition) error
 		c.logger.Error("PostConstruct方法执行失败",
 			zap.String("beanName", name),
 			zap.Error(err))
-		return fmt.Errorf("error initializing bean '%s': %w", name, err)
+		return fmt.Errorf("error initializing bean '%s': %w", c.displayName(name), err)
 	}
 
 	// 标记为已初始化
 	bean.initialized = true
+	c.initOrder = append(c.initOrder, name)
 	c.logger.Debug("bean初始化完成",
 		zap.String("beanName", name))
 	return nil
 }
 
 // injectDuringInit 在初始化过程中注入依赖，不使用容器的锁
-func (c *containerImpl) injectDuringInit(instance interface{}) error {
-	if instance == nil {
-		return errors.New("cannot inject into nil instance")
-	}
-
-	val := reflect.ValueOf(instance)
-
-	// 如果是指针，获取其元素
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
-	}
+func (c *containerImpl) injectDuringInit(instance interface{}, bean *BeanDefinition, from *initFrame) error {
+	return c.injectInto(instance, bean, c.initResolver(from))
+}
 
-	// 只能向结构体注入
-	if val.Kind() != reflect.Struct {
-		return fmt.Errorf("can only inject into struct, got %s", val.Kind())
+// getBeanForInit 在初始化过程中按名称获取bean，实例尚未创建时先创建
+func (c *containerImpl) getBeanForInit(name string, from *initFrame) (interface{}, error) {
+	// 带有前缀的名称返回FactoryBean本身
+	if factoryName, ok := trimFactoryBeanPrefix(name); ok {
+		return c.getFactoryBean(c.resolveAlias(factoryName), func(name string) (interface{}, error) {
+			return c.getRawBeanForInit(name, from)
+		})
 	}
 
-	// 获取类型
-	t := val.Type()
-
-	// 遍历所有字段
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-
-		// 检查是否有注入标签
-		injectTag := field.Tag.Get("inject")
-		if injectTag == "" {
-			continue
-		}
+	name = c.resolveAlias(name)
 
-		// 检查是否为可选依赖
-		optionalTag := field.Tag.Get("optional")
-		optional := optionalTag == "true"
-
-		// 获取要注入的bean
-		var bean interface{}
-		var err error
-
-		// 在初始化过程中，需要手动查找bean而不是使用GetSafe
-		if injectTag != "" {
-			c.mu.RLock()
-			beanDef, exists := c.beans[injectTag]
-			c.mu.RUnlock()
+	instance, err := c.getRawBeanForInit(name, from)
+	if err != nil {
+		return nil, err
+	}
+	return c.unwrapFactoryBean(name, instance, from)
+}
 
-			if !exists {
-				if optional {
-					continue
-				}
-				return fmt.Errorf("bean with name '%s' not found", injectTag)
-			}
+// getRawBeanForInit 在初始化过程中按名称获取bean，不处理FactoryBean
+func (c *containerImpl) getRawBeanForInit(name string, from *initFrame) (interface{}, error) {
+	// 在初始化过程中，需要手动查找bean而不是使用GetSafe
+	c.mu.RLock()
+	beanDef, exists := c.beans[name]
+	c.mu.RUnlock()
 
-			// 如果bean实例尚未创建，则创建它
-			if beanDef.Instance == nil {
-				c.mu.Lock()
-				if err := c.createBeanInstance(injectTag, beanDef); err != nil {
-					c.mu.Unlock()
-					if optional {
-						continue
-					}
-					return err
-				}
-				c.mu.Unlock()
-			}
+	if !exists {
+		return nil, fmt.Errorf("bean with name '%s' not found", name)
+	}
 
-			bean = beanDef.Instance
-		} else {
-			// 自动查找匹配类型的bean候选
-			bean, err = c.findCandidateByTypeForInit(field.Type)
-			if err != nil {
-				if optional {
-					continue
-				}
-				return fmt.Errorf("error injecting field '%s': %w", field.Name, err)
-			}
-		}
+	// 多例bean每次注入都创建新实例，不暴露注册的模板实例
+	switch beanDef.Scope {
+	case Prototype:
+		return c.createPrototypeInstance(beanDef, from)
+	case Keyed:
+		return nil, fmt.Errorf("bean '%s' is keyed, use GetWithKey instead", name)
+	}
 
-		// 设置字段值
-		fieldVal := val.Field(i)
-		if !fieldVal.CanSet() {
-			return fmt.Errorf("cannot set field '%s', it might be unexported", field.Name)
+	// 延迟初始化的bean被注入时完成初始化，其他初始化正在进行时等待其完成
+	// 等待会形成环说明存在循环依赖，返回已创建的实例
+	c.mu.RLock()
+	pending := !beanDef.initialized && (beanDef.Lazy || beanDef.lazyInit != nil)
+	c.mu.RUnlock()
+	if pending {
+		if err := c.initializeLazyBean(name, beanDef, from); err != nil {
+			return nil, err
 		}
+	}
 
-		beanVal := reflect.ValueOf(bean)
+	// 如果bean实例尚未创建，则创建它
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-		// 处理接口类型的注入
-		if field.Type.Kind() == reflect.Interface && !beanVal.Type().Implements(field.Type) {
-			return fmt.Errorf("bean of type %s does not implement interface %s", beanVal.Type(), field.Type)
+	if beanDef.Instance == nil {
+		if err := c.createBeanInstance(name, beanDef, from); err != nil {
+			return nil, err
 		}
-
-		fieldVal.Set(beanVal)
 	}
 
-	return nil
+	return beanDef.Instance, nil
 }
 
 // findCandidateByTypeForInit 在初始化过程中查找匹配类型的bean候选，不使用容器的锁获取bean
-func (c *containerImpl) findCandidateByTypeForInit(t reflect.Type) (interface{}, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	var candidates []string
-	var candidateInstances []interface{}
-
-	for name, bean := range c.beans {
-		if bean.Instance == nil {
-			continue // 跳过尚未初始化的bean
-		}
-
-		beanType := bean.Type
-
-		// 检查类型匹配
-		if t.Kind() == reflect.Interface {
-			// 如果要注入的是接口，检查bean是否实现了该接口
-			if beanType != nil && beanType.Implements(t) {
-				candidates = append(candidates, name)
-				candidateInstances = append(candidateInstances, bean.Instance)
-			}
-		} else if t == beanType {
-			// 直接的类型匹配
-			candidates = append(candidates, name)
-			candidateInstances = append(candidateInstances, bean.Instance)
-		}
+func (c *containerImpl) findCandidateByTypeForInit(t reflect.Type, qualifier string, from *initFrame) (interface{}, error) {
+	// 声明为Container类型的依赖注入所属容器
+	if t == containerType {
+		return c, nil
 	}
 
-	if len(candidates) == 0 {
-		return nil, fmt.Errorf("no bean candidate found for type %s", t)
-	}
-
-	if len(candidates) > 1 {
-		return nil, fmt.Errorf("multiple bean candidates found for type %s: %v", t, candidates)
+	c.mu.RLock()
+	name, err := c.selectCandidate(t, qualifier, c.matchCandidates(t))
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
 	}
 
-	// 返回唯一的候选bean实例
-	return candidateInstances[0], nil
+	// 返回唯一的候选bean实例，尚未创建时先创建
+	return c.getBeanForInit(name, from)
 }
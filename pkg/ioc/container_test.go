@@ -1,7 +1,13 @@
 package ioc_test
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/TickleLee/ioc/pkg/ioc"
 )
@@ -121,11 +127,11 @@ func TestContainer_GetSafe_BYGet(t *testing.T) {
 
 	container.Init()
 
+	// 获取不存在的bean返回错误，并不会panic
 	_, err := container.GetSafe("productService2")
-	if err != nil {
-		t.Fatal(err)
+	if err == nil {
+		t.Fatal("expected error for unknown bean")
 	}
-	// 并不会panic
 }
 
 func TestContainer_Inject(t *testing.T) {
@@ -149,7 +155,13 @@ func TestContainer_Inject_Optional(t *testing.T) {
 	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
 	container.Register("quotaService", &QuotaServiceImpl{}, ioc.Singleton)
 
-	container.Inject(&QuotaServiceImpl{})
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := container.Inject(&QuotaServiceImpl{}); err != nil {
+		t.Fatal(err)
+	}
 
 	quotaService := container.Get("quotaService").(QuotaService)
 
@@ -173,4 +185,1821 @@ func TestContainer_GetAll(t *testing.T) {
 	for _, bean := range beans {
 		t.Log(bean.Name, bean.Type)
 	}
+
+	// 返回的是副本，修改它不影响容器
+	delete(beans, "productService")
+	if _, err := container.GetSafe("productService"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 与初始化后的注册并发调用
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			container.Register(fmt.Sprintf("product%d", i), &ProductServiceImpl{}, ioc.Singleton)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		container.GetAll()
+		container.GetAllNames()
+	}
+	wg.Wait()
+	if len(container.GetAllNames()) != 22 {
+		t.Fatalf("expected 22 beans, got %v", container.GetAllNames())
+	}
+}
+
+type SetterQuotaService struct {
+	productService ProductService
+	backup         ProductService
+	initialized    bool
+}
+
+func (q *SetterQuotaService) SetProductService(productService ProductService) {
+	q.productService = productService
+}
+
+func (q *SetterQuotaService) WireBackup(backup ProductService) error {
+	q.backup = backup
+	return nil
+}
+
+func (q *SetterQuotaService) PostConstruct() error {
+	q.initialized = q.productService != nil && q.backup != nil
+	return nil
+}
+
+func TestContainer_SetterInjection(t *testing.T) {
+	container := ioc.NewContainer()
+
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("quotaService", &SetterQuotaService{}, ioc.Singleton)
+
+	if err := container.RegisterMethodInjection("quotaService", "WireBackup", "productService"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	quotaService := container.Get("quotaService").(*SetterQuotaService)
+	if !quotaService.initialized {
+		t.Fatal("setters were not invoked before PostConstruct")
+	}
+}
+
+type ConventionSetterService struct {
+	productService ProductService
+	endpoint       string
+	missing        *HTTPClient
+}
+
+func (s *ConventionSetterService) SetProductService(productService ProductService) {
+	s.productService = productService
+}
+
+func (s *ConventionSetterService) SetEndpoint(endpoint string) {
+	s.endpoint = endpoint
+}
+
+func (s *ConventionSetterService) SetHttpClient(client *HTTPClient) {
+	s.missing = client
+}
+
+func TestContainer_SetterInjection_Convention(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("endpoint", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("setterService", &ConventionSetterService{}, ioc.Singleton)
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 基本类型参数的setter和找不到同名bean的setter被跳过
+	service := container.Get("setterService").(*ConventionSetterService)
+	if service.productService == nil || service.endpoint != "" || service.missing != nil {
+		t.Fatalf("unexpected setter injection result: %+v", service)
+	}
+
+	// 同名bean类型不匹配时返回错误，而不是静默跳过
+	mismatched := ioc.NewContainer()
+	mismatched.Register("productService", &Route{Path: "/products"}, ioc.Singleton)
+	mismatched.Register("setterService", &ConventionSetterService{}, ioc.Singleton)
+	if err := mismatched.Init(); err == nil {
+		t.Fatal("expected mismatched setter dependency to fail Init")
+	}
+}
+
+type LegacyQuotaService struct {
+	productService ProductService `inject:"productService"`
+}
+
+func TestContainer_UnexportedFieldInjection(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("quotaService", &LegacyQuotaService{}, ioc.Singleton)
+
+	if err := container.Init(); err == nil {
+		t.Fatal("expected unexported field injection to be rejected by default")
+	}
+
+	container = ioc.NewContainer(ioc.WithUnexportedFieldInjection(true))
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("quotaService", &LegacyQuotaService{}, ioc.Singleton)
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	quotaService := container.Get("quotaService").(*LegacyQuotaService)
+	if quotaService.productService == nil {
+		t.Fatal("unexported field was not injected")
+	}
+}
+
+type PrimaryProductService struct {
+	ProductService
+}
+
+func (PrimaryProductService) BeanName() string {
+	return "productService"
+}
+
+func TestContainer_Invoke(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("quotaService", &QuotaServiceImpl{}, ioc.Singleton)
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := container.Invoke(func(quotaService QuotaService, productService PrimaryProductService) error {
+		if quotaService.GetQuota("123") != productService.GetProduct("123") {
+			t.Fatal("unexpected result")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+type Route struct {
+	Path string
+}
+
+type RouteResults struct {
+	ioc.Out
+
+	Product ProductService
+	Home    Route `group:"routes"`
+	Quota   Route `group:"routes"`
+}
+
+type RouterParams struct {
+	ioc.In
+
+	ProductService ProductService `name:"product"`
+	Routes         []Route        `group:"routes"`
+	Missing        QuotaService   `name:"missing" optional:"true"`
+}
+
+type Router struct {
+	Routes []Route
+}
+
+func TestContainer_RegisterConstructor(t *testing.T) {
+	container := ioc.NewContainer()
+
+	err := container.RegisterConstructor("", ioc.Singleton, func() RouteResults {
+		return RouteResults{
+			Product: &ProductServiceImpl{},
+			Home:    Route{Path: "/"},
+			Quota:   Route{Path: "/quota"},
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = container.RegisterConstructor("router", ioc.Singleton, func(p RouterParams) (*Router, error) {
+		if p.ProductService == nil || p.Missing != nil {
+			t.Fatal("unexpected parameter object")
+		}
+		return &Router{Routes: p.Routes}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	router := container.Get("router").(*Router)
+	if len(router.Routes) != 2 || router.Routes[0].Path != "/" || router.Routes[1].Path != "/quota" {
+		t.Fatalf("unexpected routes: %v", router.Routes)
+	}
+}
+
+type SplitRoutes struct {
+	ioc.Out
+
+	Primary  *Route `name:"primaryRoute"`
+	Fallback *Route `name:"fallbackRoute"`
+}
+
+func TestContainer_RegisterConstructor_ConcurrentResolve(t *testing.T) {
+	container := ioc.NewContainer(ioc.WithLazyInit(true))
+
+	var calls int32
+	err := container.RegisterConstructor("", ioc.Singleton, func() SplitRoutes {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return SplitRoutes{Primary: &Route{Path: "/"}, Fallback: &Route{Path: "/fallback"}}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 并发首次获取同一构造函数提供的bean，后到的调用等待结果而不是报告循环依赖
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, name := range []string{"primaryRoute", "fallbackRoute"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			_, err := container.GetSafe(name)
+			errs <- err
+		}(name)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("singleton constructor should be called once, got %d", calls)
+	}
+}
+
+// InitGate 每次创建副本时等待所有调用方到达
+type InitGate struct {
+	arrived *sync.WaitGroup
+}
+
+func (g *InitGate) Clone() (interface{}, error) {
+	g.arrived.Done()
+	g.arrived.Wait()
+	return &InitGate{}, nil
+}
+
+type LeftNode struct {
+	Right *RightNode
+}
+
+type RightNode struct {
+	Left *LeftNode
+}
+
+func TestContainer_RegisterConstructor_ConcurrentCycle(t *testing.T) {
+	container := ioc.NewContainer(ioc.WithLazyInit(true))
+
+	// 两个构造函数都开始调用后才继续解析彼此，确保两次调用同时进行
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	container.Register("initGate", &InitGate{arrived: &arrived}, ioc.Prototype)
+	container.RegisterConstructor("leftNode", ioc.Singleton, func(_ *InitGate, right *RightNode) *LeftNode {
+		return &LeftNode{Right: right}
+	})
+	container.RegisterConstructor("rightNode", ioc.Singleton, func(_ *InitGate, left *LeftNode) *RightNode {
+		return &RightNode{Left: left}
+	})
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不同goroutine上相互依赖的构造函数报告循环依赖，而不是互相等待
+	errs := make(chan error, 2)
+	for _, name := range []string{"leftNode", "rightNode"} {
+		go func(name string) {
+			_, err := container.GetSafe(name)
+			errs <- err
+		}(name)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if err == nil {
+				t.Fatal("expected circular constructor dependency to fail")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("concurrent resolution of a constructor cycle deadlocked")
+		}
+	}
+}
+
+type PluginResults struct {
+	ioc.Out
+
+	Report *ReportGenerator `group:"plugins"`
+}
+
+type PluginParams struct {
+	ioc.In
+
+	Plugins []*ReportGenerator `group:"plugins"`
+}
+
+func TestContainer_RegisterConstructor_GroupMembersAreRaw(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+
+	var constructed int32
+	container.RegisterConstructor("", ioc.Singleton, func() PluginResults {
+		return PluginResults{Report: &ReportGenerator{constructed: &constructed}}
+	})
+
+	var plugins []*ReportGenerator
+	container.RegisterConstructor("pluginHost", ioc.Singleton, func(p PluginParams) *Router {
+		plugins = p.Plugins
+		return &Router{}
+	})
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 值组成员按构造函数返回的原样注入，不经过依赖注入和PostConstruct
+	if len(plugins) != 1 || plugins[0].ProductService != nil || atomic.LoadInt32(&constructed) != 0 {
+		t.Fatalf("group members should be injected as raw values: %+v", plugins)
+	}
+}
+
+type BackupProductServiceImpl struct{}
+
+func (p *BackupProductServiceImpl) GetProduct(id string) string {
+	return "backup:" + id
+}
+
+type ProductController struct {
+	ProductService ProductService `inject:""`
+	Backup         ProductService `inject:"" qualifier:"backup"`
+}
+
+func TestContainer_PrimaryAndQualifier(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("backupProductService", &BackupProductServiceImpl{}, ioc.Singleton)
+	container.Register("productController", &ProductController{}, ioc.Singleton)
+
+	if err := container.SetPrimary("productService"); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.SetQualifiers("backupProductService", "backup"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	controller := container.Get("productController").(*ProductController)
+	if controller.ProductService.GetProduct("1") != "1" {
+		t.Fatal("primary bean was not selected")
+	}
+	if controller.Backup.GetProduct("1") != "backup:1" {
+		t.Fatal("qualified bean was not selected")
+	}
+}
+
+type AwareService struct {
+	Container ioc.Container `inject:""`
+
+	name      string
+	container ioc.Container
+	logger    ioc.Logger
+}
+
+func (a *AwareService) SetBeanName(name string) {
+	a.name = name
+}
+
+func (a *AwareService) SetContainer(container ioc.Container) {
+	a.container = container
+}
+
+func (a *AwareService) SetLogger(logger ioc.Logger) {
+	a.logger = logger
+}
+
+func TestContainer_Aware(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("awareService", &AwareService{}, ioc.Singleton)
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	service := container.Get("awareService").(*AwareService)
+	if service.name != "awareService" {
+		t.Fatalf("unexpected bean name: %s", service.name)
+	}
+	if service.container != container || service.Container != container {
+		t.Fatal("owning container was not injected")
+	}
+	if service.logger == nil {
+		t.Fatal("logger was not injected")
+	}
+}
+
+type QuotaChecker struct {
+	TenantID       string
+	ProductService ProductService
+	Backup         ProductService `inject:"productService"`
+	initialized    bool
+}
+
+func (q *QuotaChecker) PostConstruct() error {
+	q.initialized = true
+	return nil
+}
+
+type QuotaCheckerFactory func(tenantID string) (*QuotaChecker, error)
+
+func TestContainer_RegisterAssistedFactory(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+
+	err := container.RegisterAssistedFactory("quotaCheckerFactory", new(QuotaCheckerFactory),
+		func(tenantID string, productService ProductService) (*QuotaChecker, error) {
+			return &QuotaChecker{TenantID: tenantID, ProductService: productService}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = container.RegisterAssistedFactory("", new(QuotaCheckerFactory),
+		func(tenantID string, productService ProductService) (*QuotaChecker, error) {
+			return &QuotaChecker{TenantID: tenantID, ProductService: productService}, nil
+		})
+	if err == nil {
+		t.Fatal("expected assisted factory with empty name to be rejected")
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	factory := container.Get("quotaCheckerFactory").(QuotaCheckerFactory)
+	checker, err := factory("tenant-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checker.TenantID != "tenant-1" || checker.ProductService == nil || checker.Backup == nil || !checker.initialized {
+		t.Fatalf("unexpected checker: %+v", checker)
+	}
+}
+
+type AuditTrail struct {
+	TenantID string
+}
+
+func (a *AuditTrail) PostConstruct() error {
+	return fmt.Errorf("audit storage unavailable for %s", a.TenantID)
+}
+
+type AuditTrailFactory func(tenantID string) *AuditTrail
+
+func TestContainer_RegisterAssistedFactory_SingleReturnPanics(t *testing.T) {
+	container := ioc.NewContainer()
+	err := container.RegisterAssistedFactory("auditTrailFactory", new(AuditTrailFactory), func(tenantID string) *AuditTrail {
+		return &AuditTrail{TenantID: tenantID}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 构造函数返回error时，工厂函数类型也必须返回error
+	err = container.RegisterAssistedFactory("failingFactory", new(AuditTrailFactory), func(tenantID string) (*AuditTrail, error) {
+		return &AuditTrail{TenantID: tenantID}, nil
+	})
+	if err == nil {
+		t.Fatal("expected single-return factory type to reject a constructor that returns an error")
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不返回error的工厂函数在PostConstruct失败时panic，panic的值是error
+	defer func() {
+		if _, ok := recover().(error); !ok {
+			t.Fatal("expected single-return assisted factory to panic with an error")
+		}
+	}()
+	container.Get("auditTrailFactory").(AuditTrailFactory)("tenant-1")
+}
+
+type TenantQuota struct {
+	TenantID       string
+	ProductService ProductService `inject:"productService"`
+	destroyed      bool
+}
+
+func (q *TenantQuota) Destroy() error {
+	q.destroyed = true
+	return nil
+}
+
+func TestContainer_Keyed(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.RegisterKeyedFactory("tenantQuota", func(key string) (interface{}, error) {
+		return &TenantQuota{TenantID: key}, nil
+	})
+	if err := container.RegisterKeyedFactory("", func(key string) (interface{}, error) {
+		return &TenantQuota{TenantID: key}, nil
+	}); err == nil {
+		t.Fatal("expected keyed factory with empty name to be rejected")
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	a := container.GetWithKey("tenantQuota", "a").(*TenantQuota)
+	if a != container.GetWithKey("tenantQuota", "a") || a.ProductService == nil {
+		t.Fatal("keyed instance was not cached or injected")
+	}
+	b := container.GetWithKey("tenantQuota", "b").(*TenantQuota)
+	if a == b || b.TenantID != "b" {
+		t.Fatal("keys must have distinct instances")
+	}
+
+	if _, err := container.GetSafe("tenantQuota"); err == nil {
+		t.Fatal("expected error when getting keyed bean without key")
+	}
+
+	if err := container.EvictKey("tenantQuota", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if !a.destroyed {
+		t.Fatal("evicted instance was not destroyed")
+	}
+
+	keys, err := container.GetKeys("tenantQuota")
+	if err != nil || len(keys) != 1 || keys[0] != "b" {
+		t.Fatalf("unexpected keys: %v, %v", keys, err)
+	}
+}
+
+type ServiceConfig struct {
+	ProductService ProductService `inject:"productService"`
+}
+
+func (s *ServiceConfig) QuotaService() QuotaService {
+	return &QuotaServiceImpl{ProductService: s.ProductService}
+}
+
+func (s *ServiceConfig) Router(quotaService QuotaService) (*Router, error) {
+	return &Router{Routes: []Route{{Path: quotaService.GetQuota("/quota")}}}, nil
+}
+
+// Describe 是普通的导出方法，未在BeanMethods中列出，不会注册为bean
+func (s *ServiceConfig) Describe() string {
+	return "service config"
+}
+
+func (s *ServiceConfig) BeanMethods() []string {
+	return []string{"QuotaService", "Router"}
+}
+
+type UnmarkedConfig struct{}
+
+func (u *UnmarkedConfig) Router() *Router {
+	return &Router{}
+}
+
+// Reset 没有返回值，不是bean方法
+func (u *UnmarkedConfig) Reset() {}
+
+type BrokenConfig struct{}
+
+func (b *BrokenConfig) Route() *Route {
+	return &Route{Path: "/"}
+}
+
+func (b *BrokenConfig) Describe(prefix string) {}
+
+func (b *BrokenConfig) BeanMethods() []string {
+	return []string{"Route", "Describe"}
+}
+
+type FailingConfig struct{}
+
+func (f *FailingConfig) Route() *Route {
+	return &Route{Path: "/"}
+}
+
+func (f *FailingConfig) Router(route *Route) (*Router, error) {
+	return nil, fmt.Errorf("router unavailable")
+}
+
+func (f *FailingConfig) BeanMethodName(method string) string {
+	return map[string]string{"Route": "failingRoute", "Router": "failingRouter"}[method]
+}
+
+func TestContainer_RegisterConfiguration(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+
+	if err := container.RegisterConfiguration(&ServiceConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	router := container.Get("router").(*Router)
+	if len(router.Routes) != 1 || router.Routes[0].Path != "/quota" {
+		t.Fatalf("unexpected routes: %v", router.Routes)
+	}
+
+	if container.Get("serviceConfig").(*ServiceConfig).ProductService == nil {
+		t.Fatal("configuration was not injected")
+	}
+	if _, err := container.GetSafe("describe"); err == nil {
+		t.Fatal("methods not listed by BeanMethods should not be registered")
+	}
+
+	// 未实现BeanMethodLister时注册所有返回值有效的导出方法
+	unmarked := ioc.NewContainer()
+	if err := unmarked.RegisterConfiguration(&UnmarkedConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	names := unmarked.GetAllNames()
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"router", "unmarkedConfig"}) {
+		t.Fatalf("unexpected beans registered from configuration: %v", names)
+	}
+}
+
+func TestContainer_RegisterConfiguration_Validation(t *testing.T) {
+	container := ioc.NewContainer()
+
+	// 列出的方法签名无效时不写入任何bean
+	if err := container.RegisterConfiguration(&BrokenConfig{}); err == nil {
+		t.Fatal("expected invalid bean method to be rejected")
+	}
+	if names := container.GetAllNames(); len(names) != 0 {
+		t.Fatalf("rejected configuration should not register beans, got %v", names)
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 初始化后注册时，bean创建失败会撤销整个配置类
+	if err := container.RegisterConfiguration(&FailingConfig{}); err == nil {
+		t.Fatal("expected failing bean method to fail registration")
+	}
+	if names := container.GetAllNames(); len(names) != 0 {
+		t.Fatalf("failed configuration should be rolled back, got %v", names)
+	}
+}
+
+type ProductServiceFactory struct {
+	Prefix string
+	built  int
+}
+
+func (f *ProductServiceFactory) Object() (interface{}, error) {
+	f.built++
+	return &BackupProductServiceImpl{}, nil
+}
+
+func (f *ProductServiceFactory) ObjectType() reflect.Type {
+	return reflect.TypeOf(&BackupProductServiceImpl{})
+}
+
+func (f *ProductServiceFactory) IsSingleton() bool {
+	return true
+}
+
+type FactoryConsumer struct {
+	ProductService ProductService `inject:""`
+}
+
+func TestContainer_FactoryBean(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceFactory{}, ioc.Singleton)
+	container.Register("consumer", &FactoryConsumer{}, ioc.Singleton)
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	productService := container.Get("productService").(ProductService)
+	if productService.GetProduct("1") != "backup:1" {
+		t.Fatal("factory product was not returned")
+	}
+	if container.Get("consumer").(*FactoryConsumer).ProductService != productService {
+		t.Fatal("by-type injection did not match the factory product")
+	}
+
+	factory := container.Get(ioc.FactoryBeanPrefix + "productService").(*ProductServiceFactory)
+	if factory.built != 1 {
+		t.Fatalf("singleton product built %d times", factory.built)
+	}
+}
+
+type Repository[T any] interface {
+	FindByID(id string) (T, error)
+}
+
+type memoryRepository[T any] struct {
+	items map[string]T
+}
+
+func (r *memoryRepository[T]) FindByID(id string) (T, error) {
+	return r.items[id], nil
+}
+
+type Quota struct {
+	Limit int
+}
+
+type GenericConsumer struct {
+	Products Repository[Route] `inject:""`
+	Quotas   Repository[Quota] `inject:""`
+}
+
+func TestContainer_GenericBeans(t *testing.T) {
+	container := ioc.NewContainer()
+
+	routes := &memoryRepository[Route]{items: map[string]Route{"home": {Path: "/"}}}
+	quotas := &memoryRepository[Quota]{items: map[string]Quota{"a": {Limit: 5}}}
+	if err := ioc.RegisterGenericTo[Repository[Route]](container, routes, ioc.Singleton); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioc.RegisterGenericTo[Repository[Quota]](container, quotas, ioc.Singleton); err != nil {
+		t.Fatal(err)
+	}
+	container.Register("consumer", &GenericConsumer{}, ioc.Singleton)
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	repo, err := ioc.ResolveFrom[Repository[Route]](container)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route, _ := repo.FindByID("home"); route.Path != "/" {
+		t.Fatalf("unexpected route: %v", route)
+	}
+
+	consumer := container.Get("consumer").(*GenericConsumer)
+	if consumer.Products != routes || consumer.Quotas != quotas {
+		t.Fatal("generic fields were not wired")
+	}
+
+	if ioc.TypeBeanName[Repository[Route]]() != "Repository[Route]" {
+		t.Fatalf("unexpected bean name: %s", ioc.TypeBeanName[Repository[Route]]())
+	}
+}
+
+type Settings struct {
+	Name string
+}
+
+type ValueConsumer struct {
+	Timeout  time.Duration      `inject:"timeout"`
+	Validate func(string) error `inject:""`
+	Labels   map[string]string  `inject:"labels"`
+	Settings Settings           `inject:"settings"`
+}
+
+func TestContainer_ValueBeans(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("timeout", 5*time.Second, ioc.Singleton)
+	container.Register("validate", func(s string) error { return nil }, ioc.Singleton)
+	container.Register("labels", map[string]string{"env": "test"}, ioc.Singleton)
+	container.Register("settings", Settings{Name: "default"}, ioc.Prototype)
+	container.Register("consumer", &ValueConsumer{}, ioc.Singleton)
+
+	if err := container.Register("cache", map[string]string{}, ioc.Prototype); err == nil {
+		t.Fatal("expected prototype map bean to require a factory")
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := container.Get("consumer").(*ValueConsumer)
+	if consumer.Timeout != 5*time.Second || consumer.Validate == nil ||
+		consumer.Labels["env"] != "test" || consumer.Settings.Name != "default" {
+		t.Fatalf("unexpected consumer: %+v", consumer)
+	}
+
+	if container.Get("settings").(Settings).Name != "default" {
+		t.Fatal("prototype value bean was not copied")
+	}
+}
+
+type HTTPClient struct {
+	Timeout        time.Duration
+	ProductService ProductService `inject:"productService"`
+	initialized    bool
+}
+
+func (h *HTTPClient) PostConstruct() error {
+	h.initialized = true
+	return nil
+}
+
+type HeaderSet struct {
+	Headers map[string]string
+}
+
+func (h *HeaderSet) Clone() (interface{}, error) {
+	headers := make(map[string]string, len(h.Headers))
+	for k, v := range h.Headers {
+		headers[k] = v
+	}
+	return &HeaderSet{Headers: headers}, nil
+}
+
+func TestContainer_PrototypeTemplate(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("httpClient", &HTTPClient{Timeout: 5 * time.Second}, ioc.Prototype)
+	container.Register("headers", &HeaderSet{Headers: map[string]string{"X-Env": "test"}}, ioc.Prototype)
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	a := container.Get("httpClient").(*HTTPClient)
+	b := container.Get("httpClient").(*HTTPClient)
+	if a == b {
+		t.Fatal("prototype returned the same instance")
+	}
+	if a.Timeout != 5*time.Second || a.ProductService == nil || !a.initialized {
+		t.Fatalf("prototype was not cloned from template: %+v", a)
+	}
+
+	h1 := container.Get("headers").(*HeaderSet)
+	h1.Headers["X-Env"] = "changed"
+	if container.Get("headers").(*HeaderSet).Headers["X-Env"] != "test" {
+		t.Fatal("Cloner was not used for deep copy")
+	}
+}
+
+func TestContainer_GetByType_Prototype(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	if err := container.RegisterTypeWithScope("Client", "http", &HTTPClient{Timeout: time.Second}, ioc.Prototype); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	a := container.GetByType("Client", "http").(*HTTPClient)
+	b, err := container.GetByTypeSafe("Client", "http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b || a.Timeout != time.Second || a.ProductService == nil || !a.initialized {
+		t.Fatalf("GetByType did not resolve the prototype like GetSafe: %+v", a)
+	}
+
+	if _, err := container.GetByTypeSafe("Client", "grpc"); err == nil {
+		t.Fatal("expected error for unknown bean")
+	}
+}
+
+func TestContainer_RegisterWith(t *testing.T) {
+	container := ioc.NewContainer()
+
+	err := container.RegisterWith(&ProductServiceImpl{},
+		ioc.TypeName("Service"),
+		ioc.Name("productService"),
+		ioc.Primary(),
+		ioc.Labels(map[string]string{"layer": "service"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = container.RegisterWith(func() (interface{}, error) {
+		return &BackupProductServiceImpl{}, nil
+	}, ioc.Name("backupProductService"), ioc.Qualifiers("backup"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := container.RegisterWith(&Route{Path: "/"}, ioc.InScope(ioc.Prototype)); err != nil {
+		t.Fatal(err)
+	}
+	container.Register("productController", &ProductController{}, ioc.Singleton)
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if container.GetByType("Service", "productService") != container.Get("Service:productService") {
+		t.Fatal("typed registration was not indexed")
+	}
+	if container.Get("Route") == container.Get("Route") {
+		t.Fatal("scope option was ignored")
+	}
+
+	bean := container.GetAll()["Service:productService"]
+	if !bean.Primary || bean.Labels["layer"] != "service" {
+		t.Fatalf("options were not applied: %+v", bean)
+	}
+
+	controller := container.Get("productController").(*ProductController)
+	if controller.Backup.GetProduct("1") != "backup:1" {
+		t.Fatal("qualifier option was ignored")
+	}
+}
+
+func TestContainer_InterfaceBindings(t *testing.T) {
+	container := ioc.NewContainer()
+
+	if err := ioc.BindTo[ProductService](container, &BackupProductServiceImpl{}); err != nil {
+		t.Fatal(err)
+	}
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	if err := container.RegisterWith(&ProductServiceImpl{}, ioc.Name("other"), ioc.As(new(fmt.Stringer))); err == nil {
+		t.Fatal("expected error for binding to an unimplemented interface")
+	}
+	if err := container.RegisterWith(&ProductServiceImpl{}, ioc.Name("legacyProductService"), ioc.Qualifiers("backup")); err != nil {
+		t.Fatal(err)
+	}
+	container.Register("productController", &ProductController{}, ioc.Singleton)
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 限定符先于显式绑定筛选，未绑定的限定bean仍可被选中
+	controller := container.Get("productController").(*ProductController)
+	if _, ok := controller.Backup.(*ProductServiceImpl); !ok {
+		t.Fatalf("qualifier should select among all implementations, got %T", controller.Backup)
+	}
+	if _, ok := controller.ProductService.(*BackupProductServiceImpl); !ok {
+		t.Fatalf("bound implementation should win without a qualifier, got %T", controller.ProductService)
+	}
+
+	service, err := ioc.ResolveFrom[ProductService](container)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.GetProduct("1") != "backup:1" {
+		t.Fatal("bound implementation should win over unbound candidates")
+	}
+
+	strict := ioc.NewContainer(ioc.WithExplicitBindings(true))
+	strict.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	if err := strict.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := strict.ResolveType(reflect.TypeOf((*ProductService)(nil)).Elem()); err == nil {
+		t.Fatal("unbound bean should be hidden from interface lookups")
+	}
+}
+
+type ReportGenerator struct {
+	ProductService ProductService `inject:"productService"`
+	constructed    *int32
+}
+
+func (r *ReportGenerator) PostConstruct() error {
+	atomic.AddInt32(r.constructed, 1)
+	return nil
+}
+
+func TestContainer_LazyInit(t *testing.T) {
+	container := ioc.NewContainer()
+
+	var constructed int32
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	err := container.RegisterWith(&ReportGenerator{constructed: &constructed}, ioc.Name("reportGenerator"), ioc.Lazy(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&constructed) != 0 {
+		t.Fatal("lazy bean should not be initialized by Init")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			container.Get("reportGenerator")
+		}()
+	}
+	wg.Wait()
+
+	generator := container.Get("reportGenerator").(*ReportGenerator)
+	if atomic.LoadInt32(&constructed) != 1 || generator.ProductService == nil {
+		t.Fatalf("lazy bean should be initialized exactly once, got %d", constructed)
+	}
+
+	// 延迟初始化的bean在启动时仍校验依赖关系
+	broken := ioc.NewContainer(ioc.WithLazyInit(true))
+	broken.Register("reportGenerator", &ReportGenerator{constructed: &constructed}, ioc.Singleton)
+	if err := broken.Init(); err == nil {
+		t.Fatal("expected missing dependency of lazy bean to fail Init")
+	}
+}
+
+type SlowCache struct {
+	started chan struct{}
+	ready   int32
+}
+
+func (s *SlowCache) PostConstruct() error {
+	close(s.started)
+	time.Sleep(50 * time.Millisecond)
+	atomic.StoreInt32(&s.ready, 1)
+	return nil
+}
+
+type CacheReader struct {
+	Cache *SlowCache `inject:"slowCache"`
+}
+
+func (r *CacheReader) PostConstruct() error {
+	if atomic.LoadInt32(&r.Cache.ready) != 1 {
+		return fmt.Errorf("slowCache injected before PostConstruct completed")
+	}
+	return nil
+}
+
+func TestContainer_LazyInit_ConcurrentInjection(t *testing.T) {
+	container := ioc.NewContainer(ioc.WithLazyInit(true))
+
+	cache := &SlowCache{started: make(chan struct{})}
+	container.Register("slowCache", cache, ioc.Singleton)
+	container.Register("cacheReader", &CacheReader{}, ioc.Singleton)
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 一个goroutine正在执行slowCache的PostConstruct时，另一个goroutine通过注入获取它
+	errs := make(chan error, 1)
+	go func() {
+		_, err := container.GetSafe("slowCache")
+		errs <- err
+	}()
+	<-cache.started
+
+	if _, err := container.GetSafe("cacheReader"); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
+
+type PingService struct {
+	Pong *PongService `inject:"pongService"`
+}
+
+type PongService struct {
+	Ping *PingService `inject:"pingService"`
+}
+
+func TestContainer_LazyInit_ConcurrentCycle(t *testing.T) {
+	container := ioc.NewContainer(ioc.WithLazyInit(true))
+
+	// 两个bean都创建实例后才继续注入，确保两次延迟初始化同时进行
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	container.RegisterFactory("pingService", ioc.Singleton, func() (interface{}, error) {
+		arrived.Done()
+		arrived.Wait()
+		return &PingService{}, nil
+	})
+	container.RegisterFactory("pongService", ioc.Singleton, func() (interface{}, error) {
+		arrived.Done()
+		arrived.Wait()
+		return &PongService{}, nil
+	})
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不同goroutine上相互注入的延迟初始化bean像非延迟bean一样完成循环注入，而不是互相等待
+	errs := make(chan error, 2)
+	for _, name := range []string{"pingService", "pongService"} {
+		go func(name string) {
+			_, err := container.GetSafe(name)
+			errs <- err
+		}(name)
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("concurrent lazy initialization of a dependency cycle deadlocked")
+		}
+	}
+
+	ping := container.Get("pingService").(*PingService)
+	pong := container.Get("pongService").(*PongService)
+	if ping.Pong != pong || pong.Ping != ping {
+		t.Fatal("lazy beans in a cycle should be injected with each other")
+	}
+}
+
+type lifecycleRecorder struct {
+	name   string
+	events *[]string
+}
+
+func (l *lifecycleRecorder) PostConstruct() error {
+	*l.events = append(*l.events, "init:"+l.name)
+	return nil
+}
+
+func (l *lifecycleRecorder) Start() error {
+	*l.events = append(*l.events, "start:"+l.name)
+	return nil
+}
+
+func (l *lifecycleRecorder) Destroy() error {
+	*l.events = append(*l.events, "destroy:"+l.name)
+	return nil
+}
+
+func TestContainer_DependsOn(t *testing.T) {
+	container := ioc.NewContainer()
+
+	var events []string
+	err := container.RegisterWith(&lifecycleRecorder{name: "repository", events: &events},
+		ioc.Name("repository"), ioc.DependsOn("migrations"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.Register("migrations", &lifecycleRecorder{name: "migrations", events: &events}, ioc.Singleton)
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"init:migrations", "init:repository",
+		"start:migrations", "start:repository",
+		"destroy:repository", "destroy:migrations",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("expected %v, got %v", expected, events)
+	}
+
+	edges := container.DependencyGraph().Edges
+	if len(edges) != 1 || edges[0] != (ioc.DependencyEdge{From: "repository", To: "migrations", Kind: ioc.DependsOnDependency}) {
+		t.Fatalf("unexpected graph edges: %v", edges)
+	}
+
+	cyclic := ioc.NewContainer()
+	cyclic.RegisterWith(&ProductServiceImpl{}, ioc.Name("a"), ioc.DependsOn("b"))
+	cyclic.RegisterWith(&ProductServiceImpl{}, ioc.Name("b"), ioc.DependsOn("a"))
+	if err := cyclic.Init(); err == nil {
+		t.Fatal("expected circular depends-on to fail Init")
+	}
+}
+
+type ConnectionPool struct {
+	open   bool
+	closed bool
+}
+
+func (p *ConnectionPool) Open() error {
+	p.open = true
+	return nil
+}
+
+func (p *ConnectionPool) Shutdown() {
+	p.closed = true
+}
+
+func (p *ConnectionPool) Size(max int) int {
+	return max
+}
+
+func TestContainer_InitAndDestroyMethod(t *testing.T) {
+	container := ioc.NewContainer()
+
+	pool := &ConnectionPool{}
+	err := container.RegisterWith(pool, ioc.Name("pool"), ioc.InitMethod("Open"), ioc.DestroyMethod("Shutdown"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := container.RegisterWith(&ConnectionPool{}, ioc.Name("invalid"), ioc.InitMethod("Size")); err == nil {
+		t.Fatal("expected invalid init method signature to be rejected")
+	}
+	if err := container.RegisterWith(&ConnectionPool{}, ioc.Name("missing"), ioc.DestroyMethod("Close")); err == nil {
+		t.Fatal("expected missing destroy method to be rejected")
+	}
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if !pool.open {
+		t.Fatal("init method was not called")
+	}
+	if err := container.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !pool.closed {
+		t.Fatal("destroy method was not called")
+	}
+}
+
+func TestContainer_RegisterAlias(t *testing.T) {
+	container := ioc.NewContainer(ioc.WithAliasWarnings(true))
+
+	container.Register("productRepo.mysql", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("quotaService", &QuotaServiceImpl{}, ioc.Singleton)
+	if err := container.RegisterAlias("productService", "legacyProductService"); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.RegisterAlias("legacyProductService", "productRepo.mysql"); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.RegisterAlias("productRepo.mysql", "productService"); err == nil {
+		t.Fatal("expected alias with an existing bean name to be rejected")
+	}
+	if err := container.RegisterAlias("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.RegisterAlias("b", "a"); err == nil {
+		t.Fatal("expected circular alias to be rejected")
+	}
+	if err := container.Init(); err == nil {
+		t.Fatal("expected alias to unknown bean to fail Init")
+	}
+
+	container = ioc.NewContainer()
+	container.Register("productRepo.mysql", &ProductServiceImpl{}, ioc.Singleton)
+	container.Register("quotaService", &QuotaServiceImpl{}, ioc.Singleton)
+	container.RegisterAlias("legacyProductService", "productRepo.mysql")
+	container.RegisterAlias("productService", "legacyProductService")
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if container.Get("productService") != container.Get("productRepo.mysql") {
+		t.Fatal("alias should resolve to the same instance")
+	}
+	quota := container.Get("quotaService").(*QuotaServiceImpl)
+	if quota.ProductService != container.Get("productRepo.mysql") {
+		t.Fatal("inject tag should resolve through the alias")
+	}
+
+	expected := map[string]string{
+		"productService":       "productRepo.mysql",
+		"legacyProductService": "productRepo.mysql",
+	}
+	if aliases := container.GetAliases(); !reflect.DeepEqual(aliases, expected) {
+		t.Fatalf("expected %v, got %v", expected, aliases)
+	}
+}
+
+func TestContainer_RegisterAlias_AllRegistrationPaths(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	for _, alias := range []string{"router", "tenantQuota", "quotaCheckerFactory", "serviceConfig", "product"} {
+		if err := container.RegisterAlias(alias, "productService"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 所有注册方式都不能使用已作为别名的名称
+	if err := container.RegisterConstructor("router", ioc.Singleton, func() *Router { return &Router{} }); err == nil {
+		t.Fatal("expected constructor registration to reject an alias name")
+	}
+	if err := container.RegisterConstructor("", ioc.Singleton, func() RouteResults { return RouteResults{} }); err == nil {
+		t.Fatal("expected result object registration to reject an alias name")
+	}
+	if err := container.RegisterKeyedFactory("tenantQuota", func(key string) (interface{}, error) {
+		return &TenantQuota{TenantID: key}, nil
+	}); err == nil {
+		t.Fatal("expected keyed factory registration to reject an alias name")
+	}
+	err := container.RegisterAssistedFactory("quotaCheckerFactory", new(QuotaCheckerFactory),
+		func(tenantID string) (*QuotaChecker, error) {
+			return &QuotaChecker{TenantID: tenantID}, nil
+		})
+	if err == nil {
+		t.Fatal("expected assisted factory registration to reject an alias name")
+	}
+	if err := container.RegisterConfiguration(&ServiceConfig{}); err == nil {
+		t.Fatal("expected configuration registration to reject an alias name")
+	}
+
+	if names := container.GetAllNames(); len(names) != 1 {
+		t.Fatalf("rejected registrations should not add beans, got %v", names)
+	}
+}
+
+func TestContainer_DynamicRegistration(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := container.Register("quotaService", &QuotaServiceImpl{}, ioc.Singleton); err != nil {
+		t.Fatal(err)
+	}
+	quota := container.Get("quotaService").(*QuotaServiceImpl)
+	if quota.ProductService != container.Get("productService") {
+		t.Fatal("bean registered after Init was not injected")
+	}
+
+	var events []string
+	if err := container.Register("plugin", &lifecycleRecorder{name: "plugin", events: &events}, ioc.Singleton); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(events, []string{"init:plugin"}) {
+		t.Fatalf("bean registered after Init was not post-constructed: %v", events)
+	}
+
+	if err := container.RegisterWith(&QuotaChecker{}, ioc.Name("checker"), ioc.DependsOn("missing")); err == nil {
+		t.Fatal("expected bean with missing dependency to be rejected")
+	}
+	if _, err := container.GetSafe("checker"); err == nil {
+		t.Fatal("rejected bean should not stay registered")
+	}
+
+	if err := container.Unregister("productService"); err == nil {
+		t.Fatal("expected unregistering a bean with live dependents to fail")
+	}
+
+	// 别名链上的所有别名随bean一并移除
+	if err := container.RegisterAlias("pluginAlias", "plugin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.RegisterAlias("legacyPlugin", "pluginAlias"); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.Unregister("plugin"); err != nil {
+		t.Fatal(err)
+	}
+	for _, alias := range []string{"pluginAlias", "legacyPlugin"} {
+		if err := container.Register(alias, &ProductServiceImpl{}, ioc.Singleton); err != nil {
+			t.Fatalf("alias '%s' should be removed with its bean: %v", alias, err)
+		}
+	}
+	if !reflect.DeepEqual(events, []string{"init:plugin", "destroy:plugin"}) {
+		t.Fatalf("destroy hook was not run: %v", events)
+	}
+	if _, err := container.GetSafe("plugin"); err == nil {
+		t.Fatal("unregistered bean should not be found")
+	}
+}
+
+type PartialResults struct {
+	ioc.Out
+
+	First  *lifecycleRecorder `name:"first"`
+	Second *CacheReader       `name:"second"`
+}
+
+func TestContainer_DynamicRegistration_AllPaths(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 构造函数
+	var calls int32
+	err := container.RegisterConstructor("quotaChecker", ioc.Singleton, func(productService ProductService) *QuotaChecker {
+		atomic.AddInt32(&calls, 1)
+		return &QuotaChecker{ProductService: productService}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatal("singleton constructor registered after Init should be called immediately")
+	}
+	checker := container.Get("quotaChecker").(*QuotaChecker)
+	if checker.ProductService == nil || checker.Backup == nil || !checker.initialized {
+		t.Fatalf("constructor bean was not injected and post-constructed: %+v", checker)
+	}
+
+	// 结果对象的任一字段注册失败时，已注册的字段被撤销并销毁
+	var events []string
+	err = container.RegisterConstructor("", ioc.Singleton, func() PartialResults {
+		return PartialResults{First: &lifecycleRecorder{name: "first", events: &events}, Second: &CacheReader{}}
+	})
+	if err == nil {
+		t.Fatal("expected result object with a missing dependency to fail")
+	}
+	if _, err := container.GetSafe("first"); err == nil {
+		t.Fatal("fields of a failed result object should not stay registered")
+	}
+	if !reflect.DeepEqual(events, []string{"init:first", "destroy:first"}) {
+		t.Fatalf("registered fields of a failed result object should be destroyed, got %v", events)
+	}
+
+	// 按键工厂
+	err = container.RegisterKeyedFactory("tenantQuota", func(key string) (interface{}, error) {
+		return &TenantQuota{TenantID: key}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quota := container.GetWithKey("tenantQuota", "a").(*TenantQuota); quota.ProductService == nil {
+		t.Fatal("keyed instance was not injected")
+	}
+
+	// 辅助注入工厂
+	err = container.RegisterAssistedFactory("quotaCheckerFactory", new(QuotaCheckerFactory),
+		func(tenantID string, productService ProductService) (*QuotaChecker, error) {
+			return &QuotaChecker{TenantID: tenantID, ProductService: productService}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tenant, err := container.Get("quotaCheckerFactory").(QuotaCheckerFactory)("tenant-1"); err != nil || !tenant.initialized {
+		t.Fatalf("assisted factory registered after Init failed: %v", err)
+	}
+
+	// 配置类
+	if err := container.RegisterConfiguration(&ServiceConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if router := container.Get("router").(*Router); len(router.Routes) != 1 {
+		t.Fatalf("unexpected routes: %v", router.Routes)
+	}
+
+	// 别名在注册时校验目标
+	if err := container.RegisterAlias("legacyProductService", "productService"); err != nil {
+		t.Fatal(err)
+	}
+	if container.Get("legacyProductService") != container.Get("productService") {
+		t.Fatal("alias registered after Init should resolve to the target")
+	}
+	if err := container.RegisterAlias("missingService", "missing"); err == nil {
+		t.Fatal("expected alias to unknown bean to be rejected after Init")
+	}
+
+	// 修改已有bean定义的方法只能在初始化前调用
+	if err := container.SetPrimary("productService"); err == nil {
+		t.Fatal("expected SetPrimary after Init to fail")
+	}
+	if err := container.SetQualifiers("productService", "main"); err == nil {
+		t.Fatal("expected SetQualifiers after Init to fail")
+	}
+	if err := container.SetDependsOn("quotaChecker", "productService"); err == nil {
+		t.Fatal("expected SetDependsOn after Init to fail")
+	}
+	if err := container.RegisterMethodInjection("quotaChecker", "PostConstruct"); err == nil {
+		t.Fatal("expected RegisterMethodInjection after Init to fail")
+	}
+}
+
+func TestContainer_Override(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	container.RegisterWith(&ProductServiceImpl{}, ioc.Name("auditService"), ioc.NonOverridable())
+
+	if err := container.Register("productService", &BackupProductServiceImpl{}, ioc.Singleton); err == nil {
+		t.Fatal("expected duplicate registration to fail without override policy")
+	}
+	if err := container.Override("productService", &BackupProductServiceImpl{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.Override("auditService", &BackupProductServiceImpl{}); err == nil {
+		t.Fatal("expected non-overridable bean to reject override")
+	}
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if container.Get("productService").(ProductService).GetProduct("1") != "backup:1" {
+		t.Fatal("override was not applied")
+	}
+	if err := container.Override("productService", &ProductServiceImpl{}); err == nil {
+		t.Fatal("expected override after Init to fail")
+	}
+
+	permissive := ioc.NewContainer(ioc.WithAllowOverride(true))
+	permissive.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	if err := permissive.Register("productService", &BackupProductServiceImpl{}, ioc.Singleton); err != nil {
+		t.Fatal(err)
+	}
+	if err := permissive.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if permissive.Get("productService").(ProductService).GetProduct("1") != "backup:1" {
+		t.Fatal("duplicate registration should override with AllowOverride")
+	}
+
+	// 按类型注册的bean同样可以覆盖，类型表指向新的定义
+	typed := ioc.NewContainer(ioc.WithAllowOverride(true))
+	if err := typed.RegisterTypeWithName("Service", "svc", &ProductServiceImpl{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := typed.RegisterTypeWithName("Service", "svc", &BackupProductServiceImpl{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := typed.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if typed.GetByType("Service", "svc").(ProductService).GetProduct("1") != "backup:1" {
+		t.Fatal("typed registration should override with AllowOverride")
+	}
+	strictTyped := ioc.NewContainer()
+	strictTyped.RegisterTypeWithName("Service", "svc", &ProductServiceImpl{})
+	if err := strictTyped.RegisterTypeWithName("Service", "svc", &BackupProductServiceImpl{}); err == nil {
+		t.Fatal("expected duplicate typed registration to fail without override policy")
+	}
+
+	// 覆盖策略对构造函数、按键工厂等所有注册方式一致生效
+	mixed := ioc.NewContainer(ioc.WithAllowOverride(true))
+	mixed.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	mixed.RegisterWith(&ProductServiceImpl{}, ioc.Name("auditService"), ioc.NonOverridable())
+	err := mixed.RegisterConstructor("productService", ioc.Singleton, func() ProductService {
+		return &BackupProductServiceImpl{}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mixed.RegisterKeyedFactory("auditService", func(key string) (interface{}, error) {
+		return &TenantQuota{TenantID: key}, nil
+	}); err == nil {
+		t.Fatal("expected non-overridable bean to reject keyed factory registration")
+	}
+	if err := mixed.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if mixed.Get("productService").(ProductService).GetProduct("1") != "backup:1" {
+		t.Fatal("constructor registration should override with AllowOverride")
+	}
+
+	strict := ioc.NewContainer()
+	strict.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+	if err := strict.RegisterConstructor("productService", ioc.Singleton, func() ProductService {
+		return &BackupProductServiceImpl{}
+	}); err == nil {
+		t.Fatal("expected duplicate constructor registration to fail without override policy")
+	}
+}
+
+func TestContainer_NamingStrategy(t *testing.T) {
+	container := ioc.NewContainer()
+	container.RegisterType("Service", &ProductServiceImpl{})
+	if names := container.GetAllNames(); len(names) != 1 || names[0] != "Service:ProductServiceImpl" {
+		t.Fatalf("default naming strategy changed names: %v", names)
+	}
+
+	qualified := ioc.NewContainer(ioc.WithNamingStrategy(ioc.QualifiedTypeName))
+	if err := qualified.RegisterType("Service", &ProductServiceImpl{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "Service:github.com/TickleLee/ioc/pkg/ioc_test.ProductServiceImpl"
+	if names := qualified.GetAllNames(); len(names) != 1 || names[0] != expected {
+		t.Fatalf("expected %s, got %v", expected, names)
+	}
+
+	// 泛型注册使用容器的命名策略
+	routes := &memoryRepository[Route]{items: map[string]Route{"home": {Path: "/"}}}
+	if err := ioc.RegisterGenericTo[Repository[Route]](qualified, routes, ioc.Singleton); err != nil {
+		t.Fatal(err)
+	}
+	name := ioc.TypeBeanNameFor[Repository[Route]](qualified)
+	if name != ioc.QualifiedTypeName(reflect.TypeOf((*Repository[Route])(nil)).Elem()) {
+		t.Fatalf("unexpected generic bean name: %s", name)
+	}
+	if err := qualified.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if bean, err := qualified.GetSafe(name); err != nil || bean != routes {
+		t.Fatalf("generic bean should be registered as %s: %v", name, err)
+	}
+	if repo, err := ioc.ResolveFrom[Repository[Route]](qualified); err != nil || repo != routes {
+		t.Fatalf("generic bean should resolve by type: %v", err)
+	}
+
+	custom := ioc.NewContainer(ioc.WithNamingStrategy(func(t reflect.Type) string {
+		return "custom." + ioc.LowerCamelTypeName(t)
+	}))
+	custom.RegisterWith(&ProductServiceImpl{})
+	if err := custom.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := custom.GetSafe("custom.productServiceImpl"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContainer_Modules(t *testing.T) {
+	container := ioc.NewContainer()
+
+	productModule := &ioc.Module{
+		Name: "product",
+		Beans: []ioc.ModuleBean{
+			ioc.ProvidePrivate(&ProductServiceImpl{}, ioc.Name("productService")),
+			ioc.Provide(&QuotaServiceImpl{}, ioc.Name("quotaService")),
+		},
+	}
+	backupModule := &ioc.Module{
+		Name:     "backup",
+		Imports:  []*ioc.Module{productModule},
+		Requires: []string{"quotaService"},
+		Beans: []ioc.ModuleBean{
+			ioc.ProvidePrivate(&BackupProductServiceImpl{}, ioc.Name("productService")),
+			ioc.Provide(&QuotaServiceImpl{}, ioc.Name("backupQuotaService")),
+		},
+	}
+	if err := container.Install(backupModule); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.Install(&ioc.Module{Name: "report", Requires: []string{"productService"}}); err == nil {
+		t.Fatal("expected private bean of another module to not satisfy requirements")
+	}
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	quota := container.Get("quotaService").(*QuotaServiceImpl)
+	backupQuota := container.Get("backupQuotaService").(*QuotaServiceImpl)
+	if quota.ProductService.GetProduct("1") == backupQuota.ProductService.GetProduct("1") {
+		t.Fatal("each module should inject its own private bean")
+	}
+	if _, err := container.GetSafe("product::productService"); err == nil {
+		t.Fatal("private bean should not be visible outside its module")
+	}
+
+	graph := container.DependencyGraph()
+	if graph.Modules["backup::productService"] != "backup" || graph.Modules["quotaService"] != "product" {
+		t.Fatalf("unexpected modules in graph: %v", graph.Modules)
+	}
+}
+
+func TestContainer_Modules_InstallRollback(t *testing.T) {
+	container := ioc.NewContainer()
+	container.Register("quotaService", &QuotaServiceImpl{}, ioc.Singleton)
+
+	productModule := &ioc.Module{
+		Name: "product",
+		Beans: []ioc.ModuleBean{
+			ioc.Provide(&ProductServiceImpl{}, ioc.Name("productService")),
+			ioc.Provide(&Route{Path: "/products"}, ioc.Name("productRoute"), ioc.When(ioc.OnBean("productService"))),
+		},
+	}
+	reportModule := &ioc.Module{
+		Name:    "report",
+		Imports: []*ioc.Module{productModule},
+		Beans: []ioc.ModuleBean{
+			ioc.Provide(&QuotaServiceImpl{}, ioc.Name("quotaService")),
+		},
+	}
+
+	// 导入的模块安装成功、本模块失败时，撤销整个Install调用
+	if err := container.Install(reportModule); err == nil {
+		t.Fatal("expected duplicate bean to fail the installation")
+	}
+	if names := container.GetAllNames(); len(names) != 1 || names[0] != "quotaService" {
+		t.Fatalf("imported module beans should be rolled back, got %v", names)
+	}
+
+	// 导入的模块没有被记录为已安装，可以重新安装
+	if err := container.Install(productModule); err != nil {
+		t.Fatal(err)
+	}
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 被撤销的条件bean不再参与求值
+	if report := container.GetConditionReport(); len(report) != 1 || !report[0].Registered {
+		t.Fatalf("rolled back conditional beans should be discarded: %+v", report)
+	}
+
+	// 允许覆盖时，撤销安装恢复被覆盖的定义及其别名
+	overriding := ioc.NewContainer(ioc.WithAllowOverride(true))
+	original := &ProductServiceImpl{}
+	overriding.Register("productService", original, ioc.Singleton)
+	overriding.RegisterAlias("legacyProductService", "productService")
+	brokenModule := &ioc.Module{
+		Name: "broken",
+		Beans: []ioc.ModuleBean{
+			ioc.Provide(&BackupProductServiceImpl{}, ioc.Name("productService")),
+			ioc.Provide(nil, ioc.Name("brokenService")),
+		},
+	}
+	if err := overriding.Install(brokenModule); err == nil {
+		t.Fatal("expected nil bean to fail the installation")
+	}
+	if err := overriding.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if overriding.Get("productService") != original || overriding.Get("legacyProductService") != original {
+		t.Fatal("overridden bean definition should be restored by the rollback")
+	}
+}
+
+func TestContainer_ConditionalRegistration(t *testing.T) {
+	t.Setenv("IOC_TEST_FEATURE", "1")
+	container := ioc.NewContainer(ioc.WithProperties(map[string]string{"cache.enabled": "true"}))
+
+	// 默认实现先于应用的实现注册，仍然会被跳过
+	err := container.RegisterWith(&BackupProductServiceImpl{}, ioc.Name("defaultProductService"),
+		ioc.When(ioc.OnMissingBean(new(ProductService))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	container.Register("productService", &ProductServiceImpl{}, ioc.Singleton)
+
+	container.RegisterWith(&QuotaServiceImpl{}, ioc.Name("quotaService"),
+		ioc.When(ioc.OnBean("productService"), ioc.OnProperty("cache.enabled", "true")))
+	container.RegisterWith(&Route{Path: "/feature"}, ioc.Name("featureRoute"), ioc.When(ioc.OnEnv("IOC_TEST_FEATURE")))
+	container.RegisterWith(&Route{Path: "/disabled"}, ioc.Name("disabledRoute"),
+		ioc.When(ioc.ConditionFunc(func(ctx ioc.ConditionContext) bool { return !ctx.HasBean("featureRoute") })))
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	names := container.GetAllNames()
+	sort.Strings(names)
+	expected := []string{"featureRoute", "productService", "quotaService"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+
+	report := container.GetConditionReport()
+	if len(report) != 4 {
+		t.Fatalf("expected 4 condition outcomes, got %d", len(report))
+	}
+	if report[0].Bean != "defaultProductService" || report[0].Registered ||
+		report[0].Conditions[0].Condition != "OnMissingBean(ioc_test.ProductService)" {
+		t.Fatalf("unexpected outcome: %+v", report[0])
+	}
+}
+
+func TestContainer_ConditionalRegistration_FactoryBeans(t *testing.T) {
+	container := ioc.NewContainer()
+
+	// 声明了接口的工厂函数按接口参与类型判断
+	container.RegisterWith(func() (interface{}, error) { return &ProductServiceImpl{}, nil },
+		ioc.Name("productService"), ioc.As(new(ProductService)))
+	container.RegisterWith(&BackupProductServiceImpl{}, ioc.Name("defaultProductService"),
+		ioc.When(ioc.OnMissingBean(new(ProductService))))
+
+	// 类型尚未确定的工厂函数无法参与类型判断，诊断信息标记为Unknown
+	container.RegisterFactory("quotaService", ioc.Singleton, func() (interface{}, error) {
+		return &QuotaServiceImpl{}, nil
+	})
+	container.RegisterWith(&QuotaServiceImpl{}, ioc.Name("defaultQuotaService"),
+		ioc.When(ioc.OnMissingBean(new(QuotaService))))
+
+	if err := container.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	report := container.GetConditionReport()
+	if len(report) != 2 {
+		t.Fatalf("expected 2 condition outcomes, got %d", len(report))
+	}
+	if report[0].Registered || report[0].Conditions[0].Unknown {
+		t.Fatalf("bound factory should satisfy the type lookup: %+v", report[0])
+	}
+	result := report[1].Conditions[0]
+	if !result.Unknown || !reflect.DeepEqual(result.UntypedBeans, []string{"quotaService"}) {
+		t.Fatalf("untyped factory should be reported as unknown: %+v", report[1])
+	}
 }
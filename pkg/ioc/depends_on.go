@@ -0,0 +1,162 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// SetDependsOn 声明bean依赖的其他bean，只能在容器初始化前调用
+// 被依赖的bean在该bean创建之前完成创建、依赖注入和PostConstruct，适用于没有字段引用的副作用依赖，
+// 例如数据库迁移必须在仓储初始化之前完成
+func (c *containerImpl) SetDependsOn(name string, dependsOn ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.initialized {
+		return errors.New("cannot modify bean definitions after container initialization")
+	}
+
+	bean, exists := c.beans[name]
+	if !exists {
+		return fmt.Errorf("bean with name '%s' not found", name)
+	}
+
+	for _, dep := range dependsOn {
+		if dep == "" {
+			return errors.New("depends-on bean name cannot be empty")
+		}
+		if dep == name {
+			return fmt.Errorf("bean '%s' cannot depend on itself", name)
+		}
+		if !containsString(bean.DependsOn, dep) {
+			bean.DependsOn = append(bean.DependsOn, dep)
+		}
+	}
+
+	c.logger.Debug("成功声明bean依赖",
+		zap.String("beanName", name),
+		zap.Strings("dependsOn", bean.DependsOn))
+	return nil
+}
+
+// ensureDependsOn 确保bean声明依赖的其他bean都已完成初始化，from为发起创建的初始化帧，调用方需持有锁
+func (c *containerImpl) ensureDependsOn(name string, bean *BeanDefinition, from *initFrame) error {
+	for _, dep := range bean.DependsOn {
+		dep = c.localName(bean.Module, dep)
+		depBean, exists := c.beans[dep]
+		if !exists {
+			return fmt.Errorf("bean '%s' depends on unknown bean '%s'", c.displayName(name), dep)
+		}
+		if depBean.initialized {
+			continue
+		}
+		if depBean.Scope != Singleton {
+			return fmt.Errorf("bean '%s' depends on non-singleton bean '%s'", name, dep)
+		}
+
+		c.logger.Debug("初始化被依赖的bean",
+			zap.String("beanName", name),
+			zap.String("dependsOn", dep))
+
+		if depBean.Lazy {
+			c.mu.Unlock()
+			err := c.initializeLazyBean(dep, depBean, from)
+			c.mu.Lock()
+			if err != nil {
+				return err
+			}
+
+			// 等待会形成环时不会等待其完成初始化
+			if !depBean.initialized {
+				return fmt.Errorf("circular dependency detected for bean: %s", dep)
+			}
+			continue
+		}
+
+		if err := c.createBeanInstance(dep, depBean, from); err != nil {
+			return err
+		}
+		if err := c.initializeBean(dep, depBean, from); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDependsOn 检查声明的依赖是否存在以及是否存在循环，调用方需持有锁
+func (c *containerImpl) checkDependsOn() error {
+	names := make([]string, 0, len(c.beans))
+	for name := range c.beans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(names))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			// 从路径中截取循环部分
+			start := 0
+			for i, n := range path {
+				if n == name {
+					start = i
+				}
+			}
+			cycle := append(append([]string(nil), path[start:]...), name)
+			return fmt.Errorf("circular depends-on detected: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		bean := c.beans[name]
+		for _, dep := range bean.DependsOn {
+			dep = c.localName(bean.Module, dep)
+			depBean, exists := c.beans[dep]
+			if !exists {
+				return fmt.Errorf("bean '%s' depends on unknown bean '%s'", c.displayName(name), dep)
+			}
+			if depBean.Private && depBean.Module != bean.Module {
+				return fmt.Errorf("bean '%s' depends on bean '%s' which is private to module '%s'", c.displayName(name), dep, depBean.Module)
+			}
+			if depBean.Scope != Singleton {
+				return fmt.Errorf("bean '%s' depends on non-singleton bean '%s'", name, dep)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
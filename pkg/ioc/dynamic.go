@@ -0,0 +1,162 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// insertBean 将bean定义加入容器，容器初始化后注册的bean立即完成初始化，调用方需持有锁
+func (c *containerImpl) insertBean(bean *BeanDefinition, key string, typed bool) error {
+	if c.initialized {
+		return c.registerAfterInit(bean, key, typed)
+	}
+	return c.addBean(bean, key, typed)
+}
+
+// registerAfterInit 在容器初始化后注册bean，单例bean立即创建、注入依赖并调用PostConstruct，调用方需持有锁
+// 初始化失败时撤销注册
+func (c *containerImpl) registerAfterInit(bean *BeanDefinition, key string, typed bool) error {
+	if bean.Scope == Singleton && !bean.lazySet {
+		bean.Lazy = c.lazyInit
+	}
+
+	// 先校验依赖关系，避免注册无法完成注入的bean
+	if err := errors.Join(c.validateBean(bean.Name, bean)...); err != nil {
+		c.logger.Error("bean依赖校验失败",
+			zap.String("beanName", bean.Name),
+			zap.Error(err))
+		return err
+	}
+
+	if err := c.addBean(bean, key, typed); err != nil {
+		return err
+	}
+
+	if bean.Scope != Singleton || bean.Lazy {
+		return nil
+	}
+
+	// 初始化期间其他goroutine获取该bean时等待其完成
+	if err := c.runLazyInit(bean.Name, bean, nil); err != nil {
+		c.removeBean(bean.Name, bean)
+		c.logger.Error("初始化后注册的bean初始化失败",
+			zap.String("beanName", bean.Name),
+			zap.Error(err))
+		return err
+	}
+
+	c.logger.Debug("容器初始化后注册bean成功", zap.String("beanName", bean.Name))
+	return nil
+}
+
+// Unregister 移除bean，已初始化的单例bean和所有按键实例会被销毁
+// 仍有已初始化的bean依赖该bean时返回错误
+func (c *containerImpl) Unregister(name string) error {
+	c.mu.Lock()
+	name = c.followAlias(name)
+	bean, exists := c.beans[name]
+	if !exists {
+		c.mu.Unlock()
+		return fmt.Errorf("bean with name '%s' not found", name)
+	}
+	if bean.lazyInit != nil {
+		c.mu.Unlock()
+		return fmt.Errorf("bean '%s' is being initialized", name)
+	}
+	if dependents := c.liveDependents(name); len(dependents) > 0 {
+		c.mu.Unlock()
+		return fmt.Errorf("cannot unregister bean '%s', it is required by %v", name, dependents)
+	}
+
+	c.removeBean(name, bean)
+	entries := bean.keyedInstances
+	bean.keyedInstances = nil
+	live := bean.Scope == Singleton && bean.initialized
+	c.mu.Unlock()
+
+	c.logger.Debug("成功移除bean", zap.String("beanName", name))
+
+	// 销毁按键实例和单例实例
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := c.destroyKeyed(name, key, entries[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if live {
+		if err := c.destroyInstance(name, bean); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// liveDependents 返回依赖指定bean的已注入bean名称，调用方需持有锁
+func (c *containerImpl) liveDependents(name string) []string {
+	var dependents []string
+	for other, bean := range c.beans {
+		if other == name || !(bean.injected || bean.initialized) {
+			continue
+		}
+		for _, edge := range c.beanEdges(other, bean) {
+			if edge.To == name {
+				dependents = append(dependents, other)
+				break
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// removeBean 从容器的所有索引中移除bean，调用方需持有锁
+func (c *containerImpl) removeBean(name string, bean *BeanDefinition) {
+	c.unindexBean(name, bean)
+	c.initOrder = removeString(c.initOrder, name)
+
+	// 指向该bean的别名一并移除，先收集再删除，避免删除中间别名后无法解析别名链
+	var aliases []string
+	for alias := range c.aliases {
+		if c.followAlias(alias) == name {
+			aliases = append(aliases, alias)
+		}
+	}
+	for _, alias := range aliases {
+		delete(c.aliases, alias)
+	}
+}
+
+// unindexBean 从总表、类型表和接口索引中移除bean定义，调用方需持有锁
+func (c *containerImpl) unindexBean(name string, bean *BeanDefinition) {
+	delete(c.beans, name)
+
+	for key, registered := range c.typeRegistry[bean.TypeName] {
+		if registered == bean {
+			delete(c.typeRegistry[bean.TypeName], key)
+		}
+	}
+
+	for _, iface := range bean.Interfaces {
+		c.typeIndex[iface] = removeString(c.typeIndex[iface], name)
+	}
+}
+
+// removeString 返回移除了指定字符串的新切片
+func removeString(values []string, value string) []string {
+	result := values[:0:0]
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
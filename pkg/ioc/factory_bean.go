@@ -0,0 +1,104 @@
+package ioc
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// FactoryBeanPrefix 获取FactoryBean本身时在bean名称前添加的前缀
+// 例如 Get("httpClient") 返回FactoryBean生产的对象，Get("&httpClient") 返回FactoryBean本身
+const FactoryBeanPrefix = "&"
+
+// FactoryBean 接口定义了生产其他对象的bean
+// 以FactoryBean注册的bean按名称获取时返回其生产的对象，按类型注入时按ObjectType匹配
+type FactoryBean interface {
+	// Object 返回生产的对象
+	Object() (interface{}, error)
+
+	// ObjectType 返回生产的对象的类型
+	ObjectType() reflect.Type
+
+	// IsSingleton 返回生产的对象是否为单例，为true时Object只调用一次
+	IsSingleton() bool
+}
+
+// trimFactoryBeanPrefix 去掉名称中的FactoryBean前缀，返回是否带有前缀
+func trimFactoryBeanPrefix(name string) (string, bool) {
+	if !strings.HasPrefix(name, FactoryBeanPrefix) {
+		return name, false
+	}
+	return strings.TrimPrefix(name, FactoryBeanPrefix), true
+}
+
+// getFactoryBean 获取FactoryBean本身
+func (c *containerImpl) getFactoryBean(name string, get func(name string) (interface{}, error)) (interface{}, error) {
+	instance, err := get(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := instance.(FactoryBean); !ok {
+		return nil, fmt.Errorf("bean '%s' is not a FactoryBean", name)
+	}
+	return instance, nil
+}
+
+// unwrapFactoryBean 如果实例是FactoryBean，返回其生产的对象，否则原样返回，from为发起查找的初始化帧
+func (c *containerImpl) unwrapFactoryBean(name string, instance interface{}, from *initFrame) (interface{}, error) {
+	factory, ok := instance.(FactoryBean)
+	if !ok {
+		return instance, nil
+	}
+
+	c.mu.Lock()
+	bean := c.beans[name]
+
+	// 容器管理的FactoryBean必须先完成注入和初始化才能生产对象
+	if bean != nil && bean.Instance == instance && !bean.initialized {
+		guard := FactoryBeanPrefix + name
+		if c.initializing[guard] {
+			c.mu.Unlock()
+			return nil, fmt.Errorf("circular dependency detected for bean: %s", name)
+		}
+		c.initializing[guard] = true
+		err := c.initializeBean(name, bean, from)
+		delete(c.initializing, guard)
+		if err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+	}
+
+	// 只有容器中的单例FactoryBean才缓存生产的对象
+	cacheable := bean != nil && bean.Instance == instance && factory.IsSingleton()
+	if cacheable && bean.factoryObject != nil {
+		object := bean.factoryObject
+		c.mu.Unlock()
+		return object, nil
+	}
+	c.mu.Unlock()
+
+	object, err := factory.Object()
+	if err != nil {
+		return nil, fmt.Errorf("error creating object from FactoryBean '%s': %w", name, err)
+	}
+	if object == nil {
+		return nil, fmt.Errorf("FactoryBean '%s' returned nil object", name)
+	}
+
+	if cacheable {
+		c.mu.Lock()
+		if bean.factoryObject == nil {
+			bean.factoryObject = object
+			c.logger.Debug("FactoryBean生产单例对象",
+				zap.String("beanName", name),
+				zap.String("type", reflect.TypeOf(object).String()))
+		}
+		object = bean.factoryObject
+		c.mu.Unlock()
+	}
+
+	return object, nil
+}
@@ -0,0 +1,121 @@
+package ioc
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// 类型名称中的包路径前缀，例如 github.com/TickleLee/ioc/examples/cases/modules/product.
+var packagePathPattern = regexp.MustCompile(`(?:[\w\-]+[./])+`)
+
+// simpleTypeName 返回不含包路径的类型名称，指针返回其元素类型的名称
+// 泛型实例化的类型参数同样去掉包路径，例如 Repository[Product]
+func simpleTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return packagePathPattern.ReplaceAllString(t.String(), "")
+}
+
+// typeOf 返回类型参数T的反射类型，T为接口时返回接口类型本身
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// TypeBeanName 返回以类型T为键向默认容器注册时使用的bean名称，例如 Repository[Product]
+func TypeBeanName[T any]() string {
+	return TypeBeanNameFor[T](getDefaultContainer())
+}
+
+// TypeBeanNameFor 返回以类型T为键向指定容器注册时使用的bean名称，由容器的命名策略生成
+func TypeBeanNameFor[T any](c Container) string {
+	return c.BeanNameFor(typeOf[T]())
+}
+
+// BeanNameFor 按容器的命名策略返回类型t对应的bean名称
+func (c *containerImpl) BeanNameFor(t reflect.Type) string {
+	return c.naming(t)
+}
+
+// ResolveType 按反射类型查找唯一匹配的依赖，多个候选时选择首选bean或返回错误
+func (c *containerImpl) ResolveType(t reflect.Type) (interface{}, error) {
+	if t == nil {
+		return nil, fmt.Errorf("cannot resolve nil type")
+	}
+	return c.findCandidateByType(t, "")
+}
+
+// RegisterGenericTo 以类型T为键向指定容器注册依赖，bean名称为 TypeBeanNameFor[T](c)
+// 适用于泛型实例化的类型，例如 RegisterGenericTo[Repository[Product]](c, repo, Singleton)
+func RegisterGenericTo[T any](c Container, instance T, scope Scope) error {
+	name := TypeBeanNameFor[T](c)
+	if name == "" {
+		return fmt.Errorf("naming strategy returned empty name for type %s", typeOf[T]())
+	}
+	return c.Register(name, instance, scope)
+}
+
+// RegisterGeneric 以类型T为键向默认容器注册依赖
+func RegisterGeneric[T any](instance T, scope Scope) error {
+	return RegisterGenericTo[T](getDefaultContainer(), instance, scope)
+}
+
+// ResolveFrom 从指定容器中按类型T查找唯一匹配的依赖，按类型匹配，与bean名称和命名策略无关
+func ResolveFrom[T any](c Container) (T, error) {
+	var zero T
+
+	bean, err := c.ResolveType(typeOf[T]())
+	if err != nil {
+		return zero, err
+	}
+
+	v, ok := bean.(T)
+	if !ok {
+		return zero, fmt.Errorf("bean of type %T is not assignable to %s", bean, typeOf[T]())
+	}
+	return v, nil
+}
+
+// Resolve 从默认容器中按类型T查找唯一匹配的依赖
+func Resolve[T any]() (T, error) {
+	return ResolveFrom[T](getDefaultContainer())
+}
+
+// ResolveNamedFrom 从指定容器中按名称获取依赖并转换为类型T
+func ResolveNamedFrom[T any](c Container, name string) (T, error) {
+	var zero T
+
+	bean, err := c.GetSafe(name)
+	if err != nil {
+		return zero, err
+	}
+
+	v, ok := bean.(T)
+	if !ok {
+		return zero, fmt.Errorf("bean '%s' of type %T is not assignable to %s", name, bean, typeOf[T]())
+	}
+	return v, nil
+}
+
+// ResolveNamed 从默认容器中按名称获取依赖并转换为类型T
+func ResolveNamed[T any](name string) (T, error) {
+	return ResolveNamedFrom[T](getDefaultContainer(), name)
+}
+
+// BindTo 向指定容器注册实现并绑定到接口I，实例在编译期即被检查是否实现了I，例如
+//
+//	BindTo[product.ProductService](c, &impl.ProductServiceImpl{})
+//
+// 按接口I查找时直接命中绑定的实现，未指定名称时按命名策略生成
+func BindTo[I any](c Container, instance I, opts ...RegisterOption) error {
+	if typeOf[I]().Kind() != reflect.Interface {
+		return fmt.Errorf("cannot bind to non-interface type %s", typeOf[I]())
+	}
+	return c.RegisterWith(instance, append([]RegisterOption{AsType[I]()}, opts...)...)
+}
+
+// Bind 向默认容器注册实现并绑定到接口I
+func Bind[I any](instance I, opts ...RegisterOption) error {
+	return BindTo[I](getDefaultContainer(), instance, opts...)
+}
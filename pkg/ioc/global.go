@@ -1,6 +1,7 @@
 package ioc
 
 import (
+	"reflect"
 	"sync"
 )
 
@@ -23,6 +24,26 @@ func getDefaultContainer() Container {
 	return defaultContainer
 }
 
+// ConfigureContainer 配置默认容器
+// 应在注册任何bean之前调用
+func ConfigureContainer(opts ...ContainerOption) {
+	c, ok := getDefaultContainer().(*containerImpl)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// RegisterWith 使用注册选项注册依赖到默认容器
+func RegisterWith(instanceOrFactory interface{}, opts ...RegisterOption) error {
+	return getDefaultContainer().RegisterWith(instanceOrFactory, opts...)
+}
+
 // Register 注册依赖到默认容器
 func Register(name string, instance interface{}, scope Scope) error {
 	return getDefaultContainer().Register(name, instance, scope)
@@ -38,11 +59,51 @@ func RegisterTypeWithName(typeName string, name string, instance interface{}) er
 	return getDefaultContainer().RegisterTypeWithName(typeName, name, instance)
 }
 
+// RegisterTypeWithScope 按类型、名称和作用域注册依赖到默认容器
+func RegisterTypeWithScope(typeName string, name string, instance interface{}, scope Scope) error {
+	return getDefaultContainer().RegisterTypeWithScope(typeName, name, instance, scope)
+}
+
 // RegisterFactory 注册依赖工厂到默认容器
 func RegisterFactory(name string, scope Scope, factory func() (interface{}, error)) error {
 	return getDefaultContainer().RegisterFactory(name, scope, factory)
 }
 
+// RegisterConstructor 注册构造函数到默认容器
+func RegisterConstructor(name string, scope Scope, constructor interface{}) error {
+	return getDefaultContainer().RegisterConstructor(name, scope, constructor)
+}
+
+// RegisterConfiguration 注册配置类到默认容器
+func RegisterConfiguration(configuration interface{}) error {
+	return getDefaultContainer().RegisterConfiguration(configuration)
+}
+
+// RegisterAssistedFactory 注册辅助注入工厂到默认容器
+func RegisterAssistedFactory(name string, factoryType interface{}, constructor interface{}) error {
+	return getDefaultContainer().RegisterAssistedFactory(name, factoryType, constructor)
+}
+
+// RegisterKeyedFactory 注册按键创建实例的工厂函数到默认容器
+func RegisterKeyedFactory(name string, factory func(key string) (interface{}, error)) error {
+	return getDefaultContainer().RegisterKeyedFactory(name, factory)
+}
+
+// SetPrimary 将默认容器中的bean标记为首选bean
+func SetPrimary(name string) error {
+	return getDefaultContainer().SetPrimary(name)
+}
+
+// SetQualifiers 为默认容器中的bean添加限定符
+func SetQualifiers(name string, qualifiers ...string) error {
+	return getDefaultContainer().SetQualifiers(name, qualifiers...)
+}
+
+// RegisterMethodInjection 为默认容器中已注册的bean声明方法注入
+func RegisterMethodInjection(name string, method string, dependencies ...string) error {
+	return getDefaultContainer().RegisterMethodInjection(name, method, dependencies...)
+}
+
 // Get 从默认容器获取依赖
 func Get(name string) interface{} {
 	return getDefaultContainer().Get(name)
@@ -53,11 +114,46 @@ func GetSafe(name string) (interface{}, error) {
 	return getDefaultContainer().GetSafe(name)
 }
 
+// GetWithKey 从默认容器获取指定键对应的实例
+func GetWithKey(name string, key string) interface{} {
+	return getDefaultContainer().GetWithKey(name, key)
+}
+
+// GetWithKeySafe 安全地从默认容器获取指定键对应的实例
+func GetWithKeySafe(name string, key string) (interface{}, error) {
+	return getDefaultContainer().GetWithKeySafe(name, key)
+}
+
+// EvictKey 移除默认容器中指定键对应的实例
+func EvictKey(name string, key string) error {
+	return getDefaultContainer().EvictKey(name, key)
+}
+
+// EvictAllKeys 移除默认容器中所有键对应的实例
+func EvictAllKeys(name string) error {
+	return getDefaultContainer().EvictAllKeys(name)
+}
+
+// GetKeys 获取默认容器中当前存活实例的所有键
+func GetKeys(name string) ([]string, error) {
+	return getDefaultContainer().GetKeys(name)
+}
+
 // GetByType 按类型从默认容器获取依赖
 func GetByType(typeName string, name string) interface{} {
 	return getDefaultContainer().GetByType(typeName, name)
 }
 
+// GetByTypeSafe 安全地按类型从默认容器获取依赖
+func GetByTypeSafe(typeName string, name string) (interface{}, error) {
+	return getDefaultContainer().GetByTypeSafe(typeName, name)
+}
+
+// ResolveType 按反射类型从默认容器查找唯一匹配的依赖
+func ResolveType(t reflect.Type) (interface{}, error) {
+	return getDefaultContainer().ResolveType(t)
+}
+
 // GetAll 获取所有注册的bean
 func GetAll() map[string]*BeanDefinition {
 	return getDefaultContainer().GetAll()
@@ -73,6 +169,11 @@ func Inject(instance interface{}) error {
 	return getDefaultContainer().Inject(instance)
 }
 
+// Invoke 调用函数，参数从默认容器中解析
+func Invoke(fn interface{}) error {
+	return getDefaultContainer().Invoke(fn)
+}
+
 // Init 初始化默认容器
 func Init() error {
 	return getDefaultContainer().Init()
@@ -107,3 +208,58 @@ func EnableDebugLogging() {
 func GetContainerLogger() Logger {
 	return GetLogger()
 }
+
+// Validate 校验默认容器中所有bean的依赖关系
+func Validate() error {
+	return getDefaultContainer().Validate()
+}
+
+// SetDependsOn 声明默认容器中bean依赖的其他bean
+func SetDependsOn(name string, dependsOn ...string) error {
+	return getDefaultContainer().SetDependsOn(name, dependsOn...)
+}
+
+// GetDependencyGraph 导出默认容器中bean的依赖关系图
+func GetDependencyGraph() *DependencyGraph {
+	return getDefaultContainer().DependencyGraph()
+}
+
+// Start 启动默认容器
+func Start() error {
+	return getDefaultContainer().Start()
+}
+
+// Close 关闭默认容器
+func Close() error {
+	return getDefaultContainer().Close()
+}
+
+// RegisterAlias 为默认容器中的bean注册别名
+func RegisterAlias(alias string, target string) error {
+	return getDefaultContainer().RegisterAlias(alias, target)
+}
+
+// GetAliases 获取默认容器中所有别名及其指向的bean名称
+func GetAliases() map[string]string {
+	return getDefaultContainer().GetAliases()
+}
+
+// Unregister 从默认容器中移除bean
+func Unregister(name string) error {
+	return getDefaultContainer().Unregister(name)
+}
+
+// Override 替换默认容器中已注册bean的实例或工厂函数
+func Override(name string, instanceOrFactory interface{}) error {
+	return getDefaultContainer().Override(name, instanceOrFactory)
+}
+
+// Install 向默认容器安装模块
+func Install(module *Module) error {
+	return getDefaultContainer().Install(module)
+}
+
+// GetConditionReport 获取默认容器中条件注册的求值结果
+func GetConditionReport() []ConditionOutcome {
+	return getDefaultContainer().GetConditionReport()
+}
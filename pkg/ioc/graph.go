@@ -0,0 +1,189 @@
+package ioc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// 依赖关系的种类
+const (
+	// InjectDependency 通过inject标签注入的依赖
+	InjectDependency = "inject"
+
+	// MethodDependency 通过方法注入声明的依赖
+	MethodDependency = "method"
+
+	// DependsOnDependency 通过DependsOn声明的初始化顺序依赖
+	DependsOnDependency = "depends-on"
+)
+
+// DependencyEdge 表示bean之间的一条依赖关系
+type DependencyEdge struct {
+	// 依赖方
+	From string
+
+	// 被依赖方
+	To string
+
+	// 依赖的种类
+	Kind string
+}
+
+// DependencyGraph 表示容器中bean的依赖关系图
+type DependencyGraph struct {
+	// 所有bean名称，按字典序排列
+	Beans []string
+
+	// 所有依赖关系，按依赖方、被依赖方排列
+	Edges []DependencyEdge
+
+	// 别名到最终指向的bean名称的映射
+	Aliases map[string]string
+
+	// 模块中的bean名称到模块名称的映射
+	Modules map[string]string
+}
+
+// DependencyGraph 导出bean的依赖关系图
+// 依赖关系根据inject标签、方法注入和DependsOn声明静态分析得出，无法唯一确定的按类型依赖不会出现在图中
+func (c *containerImpl) DependencyGraph() *DependencyGraph {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	g := &DependencyGraph{
+		Aliases: make(map[string]string, len(c.aliases)),
+		Modules: make(map[string]string),
+	}
+	for alias := range c.aliases {
+		g.Aliases[alias] = c.followAlias(alias)
+	}
+	for name, bean := range c.beans {
+		g.Beans = append(g.Beans, name)
+		if bean.Module != "" {
+			g.Modules[name] = bean.Module
+		}
+		g.Edges = append(g.Edges, c.beanEdges(name, bean)...)
+	}
+
+	sort.Strings(g.Beans)
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return a.Kind < b.Kind
+	})
+	return g
+}
+
+// beanEdges 返回bean的所有依赖关系，调用方需持有锁
+func (c *containerImpl) beanEdges(name string, bean *BeanDefinition) []DependencyEdge {
+	var edges []DependencyEdge
+
+	for _, dep := range bean.DependsOn {
+		edges = append(edges, DependencyEdge{From: name, To: c.localName(bean.Module, dep), Kind: DependsOnDependency})
+	}
+
+	for _, mi := range bean.InjectMethods {
+		for _, dep := range mi.Dependencies {
+			if dep != "" {
+				dep, _ = trimFactoryBeanPrefix(dep)
+				edges = append(edges, DependencyEdge{From: name, To: c.localName(bean.Module, dep), Kind: MethodDependency})
+			}
+		}
+	}
+
+	t := bean.Type
+	if t == nil {
+		return edges
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return edges
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		injectTag, ok := field.Tag.Lookup("inject")
+		if !ok {
+			continue
+		}
+
+		dep := injectTag
+		if dep == "" {
+			if field.Type == containerType {
+				continue
+			}
+			var err error
+			dep, err = c.selectCandidate(field.Type, field.Tag.Get("qualifier"), c.candidatesFor(bean.Module, field.Type))
+			if err != nil {
+				continue
+			}
+		}
+		dep, _ = trimFactoryBeanPrefix(dep)
+		edges = append(edges, DependencyEdge{From: name, To: c.localName(bean.Module, dep), Kind: InjectDependency})
+	}
+
+	return edges
+}
+
+// DOT 以Graphviz DOT格式输出依赖关系图，DependsOn依赖以虚线表示
+// 模块中的bean分组显示，并以模块名为前缀
+func (g *DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph beans {\n")
+
+	var modules []string
+	members := make(map[string][]string)
+	for _, name := range g.Beans {
+		module, ok := g.Modules[name]
+		if !ok {
+			fmt.Fprintf(&b, "\t%q;\n", name)
+			continue
+		}
+		if _, seen := members[module]; !seen {
+			modules = append(modules, module)
+		}
+		members[module] = append(members[module], name)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		fmt.Fprintf(&b, "\tsubgraph %q {\n\t\tlabel=%q;\n", "cluster_"+module, module)
+		for _, name := range members[module] {
+			label := name
+			if !strings.HasPrefix(name, module+moduleSeparator) {
+				label = module + moduleSeparator + name
+			}
+			fmt.Fprintf(&b, "\t\t%q [label=%q];\n", name, label)
+		}
+		b.WriteString("\t}\n")
+	}
+
+	for _, e := range g.Edges {
+		style := ""
+		if e.Kind == DependsOnDependency {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(&b, "\t%q -> %q%s;\n", e.From, e.To, style)
+	}
+
+	// 别名以点线连接到其指向的bean
+	aliases := make([]string, 0, len(g.Aliases))
+	for alias := range g.Aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		fmt.Fprintf(&b, "\t%q [shape=plaintext];\n", alias)
+		fmt.Fprintf(&b, "\t%q -> %q [style=dotted];\n", alias, g.Aliases[alias])
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
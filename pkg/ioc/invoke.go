@@ -0,0 +1,117 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// Qualified 由函数参数的包装类型实现，表示按名称而不是按类型解析依赖
+// 包装类型必须是只有一个导出字段的结构体，解析到的bean会被赋值给该字段，例如：
+//
+//	type MySQLRepo struct{ product.ProductRepository }
+//
+//	func (MySQLRepo) BeanName() string { return "mysqlProductRepository" }
+type Qualified interface {
+	BeanName() string
+}
+
+// qualified接口的反射类型
+var qualifiedType = reflect.TypeOf((*Qualified)(nil)).Elem()
+
+// Invoke 调用函数，函数的参数从容器中按类型解析
+// 函数可以没有返回值，或者最后一个返回值为error，该error会作为Invoke的结果返回
+func (c *containerImpl) Invoke(fn interface{}) error {
+	if fn == nil {
+		return errors.New("cannot invoke nil function")
+	}
+
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func {
+		return fmt.Errorf("can only invoke a function, got %s", ft.Kind())
+	}
+	if ft.IsVariadic() {
+		return fmt.Errorf("cannot invoke variadic function %s", ft)
+	}
+	if ft.NumOut() > 0 && ft.Out(ft.NumOut()-1) != errorType {
+		return fmt.Errorf("the last result of function %s must be an error", ft)
+	}
+
+	// 解析所有参数
+	r := c.runtimeResolver()
+	args := make([]reflect.Value, ft.NumIn())
+	for i := range args {
+		arg, err := c.resolveParam(ft.In(i), r)
+		if err != nil {
+			c.logger.Error("解析函数参数失败",
+				zap.String("function", ft.String()),
+				zap.Int("index", i),
+				zap.Error(err))
+			return fmt.Errorf("error resolving parameter %d of %s: %w", i, ft, err)
+		}
+		args[i] = arg
+	}
+
+	out := fv.Call(args)
+	if len(out) > 0 {
+		if errVal := out[len(out)-1]; !errVal.IsNil() {
+			return errVal.Interface().(error)
+		}
+	}
+	return nil
+}
+
+// resolveParam 从容器中解析一个函数参数
+func (c *containerImpl) resolveParam(t reflect.Type, r *dependencyResolver) (reflect.Value, error) {
+	// 参数对象，逐个字段解析
+	if isParamObject(t) {
+		return c.resolveParamObject(t, r)
+	}
+
+	// 包装类型，按名称解析
+	if t.Kind() == reflect.Struct && t.Implements(qualifiedType) {
+		return c.resolveQualified(t, r)
+	}
+
+	dep, err := r.byType(t, "")
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return assignableValue(dep, t)
+}
+
+// resolveQualified 按包装类型声明的名称解析依赖，并赋值给包装类型的唯一导出字段
+func (c *containerImpl) resolveQualified(t reflect.Type, r *dependencyResolver) (reflect.Value, error) {
+	wrapper := reflect.New(t).Elem()
+
+	fieldIndex := -1
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		if fieldIndex >= 0 {
+			return reflect.Value{}, fmt.Errorf("qualified type %s must have exactly one exported field", t)
+		}
+		fieldIndex = i
+	}
+	if fieldIndex < 0 {
+		return reflect.Value{}, fmt.Errorf("qualified type %s must have exactly one exported field", t)
+	}
+
+	name := wrapper.Interface().(Qualified).BeanName()
+	dep, err := r.byName(name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	field := wrapper.Field(fieldIndex)
+	v, err := assignableValue(dep, field.Type())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	field.Set(v)
+	return wrapper, nil
+}
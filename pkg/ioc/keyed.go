@@ -0,0 +1,232 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// DisposableBean 接口定义了对象销毁的方法
+type DisposableBean interface {
+	// Destroy 方法在对象被容器移除时调用
+	Destroy() error
+}
+
+// keyedEntry 表示Keyed作用域下某个键对应的实例
+type keyedEntry struct {
+	// 保证每个键只创建一次
+	once sync.Once
+
+	// 创建的实例
+	instance interface{}
+
+	// 创建过程中的错误
+	err error
+}
+
+// RegisterKeyedFactory 注册按键创建实例的工厂函数，bean的作用域为Keyed
+// 每个键第一次通过GetWithKey获取时调用工厂函数，创建的实例完成依赖注入和PostConstruct后按键缓存
+func (c *containerImpl) RegisterKeyedFactory(name string, factory func(key string) (interface{}, error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if name == "" {
+		return errors.New("bean name cannot be empty")
+	}
+	if factory == nil {
+		return errors.New("factory function cannot be nil")
+	}
+
+	err := c.insertBean(&BeanDefinition{
+		Name:         name,
+		Scope:        Keyed,
+		KeyedFactory: factory,
+		source:       callerSite(),
+	}, "", false)
+	if err != nil {
+		return err
+	}
+	c.logger.Debug("成功注册按键工厂", zap.String("beanName", name))
+	return nil
+}
+
+// GetWithKey 获取指定键对应的实例，不存在时创建
+func (c *containerImpl) GetWithKey(name string, key string) interface{} {
+	instance, err := c.GetWithKeySafe(name, key)
+	if err != nil {
+		c.logger.Error("获取bean失败",
+			zap.String("beanName", name),
+			zap.String("key", key),
+			zap.Error(err))
+		panic(err)
+	}
+	return instance
+}
+
+// GetWithKeySafe 安全地获取指定键对应的实例，返回错误而不是panic
+func (c *containerImpl) GetWithKeySafe(name string, key string) (interface{}, error) {
+	c.mu.Lock()
+	bean, err := c.keyedBean(name)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	if c.currentPhase == NotInitialized {
+		c.mu.Unlock()
+		return nil, errors.New("container not initialized, call Init() first")
+	}
+
+	if bean.keyedInstances == nil {
+		bean.keyedInstances = make(map[string]*keyedEntry)
+	}
+	entry, exists := bean.keyedInstances[key]
+	if !exists {
+		entry = &keyedEntry{}
+		bean.keyedInstances[key] = entry
+	}
+	c.mu.Unlock()
+
+	// 在锁外创建实例，创建过程中可能需要获取其他bean
+	entry.once.Do(func() {
+		c.logger.Debug("创建按键实例",
+			zap.String("beanName", name),
+			zap.String("key", key))
+		entry.instance, entry.err = c.createKeyedInstance(bean, key)
+	})
+
+	if entry.err != nil {
+		// 创建失败的键不缓存，下次获取时重试
+		c.mu.Lock()
+		if bean.keyedInstances[key] == entry {
+			delete(bean.keyedInstances, key)
+		}
+		c.mu.Unlock()
+		return nil, fmt.Errorf("error creating instance for bean '%s' with key '%s': %w", name, key, entry.err)
+	}
+
+	return entry.instance, nil
+}
+
+// createKeyedInstance 为指定键创建实例并完成初始化
+func (c *containerImpl) createKeyedInstance(bean *BeanDefinition, key string) (interface{}, error) {
+	// 没有按键工厂时按Prototype的方式创建
+	if bean.KeyedFactory == nil {
+		return c.createPrototypeInstance(bean, nil)
+	}
+
+	instance, err := bean.KeyedFactory(key)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.New("keyed factory returned nil instance")
+	}
+
+	if err := c.initializePrototype(instance, bean, nil); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// EvictKey 移除指定键对应的实例，实例实现了DisposableBean时调用其Destroy方法
+func (c *containerImpl) EvictKey(name string, key string) error {
+	c.mu.Lock()
+	bean, err := c.keyedBean(name)
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+
+	entry, exists := bean.keyedInstances[key]
+	if !exists {
+		c.mu.Unlock()
+		return fmt.Errorf("bean '%s' has no instance for key '%s'", name, key)
+	}
+	delete(bean.keyedInstances, key)
+	c.mu.Unlock()
+
+	return c.destroyKeyed(name, key, entry)
+}
+
+// EvictAllKeys 移除所有键对应的实例
+func (c *containerImpl) EvictAllKeys(name string) error {
+	c.mu.Lock()
+	bean, err := c.keyedBean(name)
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+
+	entries := bean.keyedInstances
+	bean.keyedInstances = nil
+	c.mu.Unlock()
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		if err := c.destroyKeyed(name, key, entries[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetKeys 返回当前存活实例的所有键，按字典序排列
+func (c *containerImpl) GetKeys(name string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	bean, err := c.keyedBean(name)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(bean.keyedInstances))
+	for key := range bean.keyedInstances {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// keyedBean 获取Keyed作用域的bean定义，调用方需持有锁
+func (c *containerImpl) keyedBean(name string) (*BeanDefinition, error) {
+	bean, exists := c.beans[c.canonicalName(name)]
+	if !exists {
+		return nil, fmt.Errorf("bean with name '%s' not found", name)
+	}
+	if bean.Scope != Keyed {
+		return nil, fmt.Errorf("bean '%s' is not keyed", name)
+	}
+	return bean, nil
+}
+
+// destroyKeyed 销毁按键实例
+func (c *containerImpl) destroyKeyed(name string, key string, entry *keyedEntry) error {
+	// 等待可能仍在进行的创建过程
+	entry.once.Do(func() {})
+	if entry.err != nil || entry.instance == nil {
+		return nil
+	}
+
+	disposable, ok := entry.instance.(DisposableBean)
+	if !ok {
+		return nil
+	}
+
+	c.logger.Debug("销毁按键实例",
+		zap.String("beanName", name),
+		zap.String("key", key))
+	if err := disposable.Destroy(); err != nil {
+		return fmt.Errorf("error destroying bean '%s' with key '%s': %w", name, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package ioc
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// initFrame 表示一次进行中的初始化：延迟初始化一个bean，或者调用一次单例构造函数
+// 初始化过程中发起的依赖查找都携带所在的帧。嵌套初始化和等待其他goroutine时记录帧之间的等待关系，
+// 沿等待关系能回到发起查找的帧说明存在循环依赖，此时不再等待，避免死锁
+type initFrame struct {
+	// 初始化结束后关闭
+	done chan struct{}
+
+	// 当前等待完成的帧，由容器的frameMu保护
+	waitingOn *initFrame
+}
+
+// newInitFrame 创建新的初始化帧
+func newInitFrame() *initFrame {
+	return &initFrame{done: make(chan struct{})}
+}
+
+// waitFor 记录from等待target完成，等待会形成环时不记录并返回false
+// from为nil表示查找不属于任何初始化过程，其他帧不会等待它，因此不会形成环
+func (c *containerImpl) waitFor(from, target *initFrame) bool {
+	if from == nil {
+		return true
+	}
+
+	c.frameMu.Lock()
+	defer c.frameMu.Unlock()
+	for f := target; f != nil; f = f.waitingOn {
+		if f == from {
+			return false
+		}
+	}
+	from.waitingOn = target
+	return true
+}
+
+// stopWaiting 清除from的等待关系
+func (c *containerImpl) stopWaiting(from *initFrame) {
+	if from == nil {
+		return
+	}
+
+	c.frameMu.Lock()
+	from.waitingOn = nil
+	c.frameMu.Unlock()
+}
+
+// initializeLazyBean 初始化延迟初始化的单例bean，并发调用时只初始化一次
+// from为发起查找的初始化帧。其他初始化正在进行时等待其完成；等待会形成环说明存在循环依赖，
+// 此时不再等待，实例已创建时由调用方使用已创建的实例，否则返回错误
+func (c *containerImpl) initializeLazyBean(name string, bean *BeanDefinition, from *initFrame) error {
+	c.mu.Lock()
+	if bean.initialized {
+		c.mu.Unlock()
+		return nil
+	}
+
+	if frame := bean.lazyInit; frame != nil {
+		if !c.waitFor(from, frame) {
+			created := bean.Instance != nil
+			c.mu.Unlock()
+			if !created {
+				return fmt.Errorf("circular dependency detected for bean: %s", name)
+			}
+			return nil
+		}
+
+		c.mu.Unlock()
+		<-frame.done
+		c.stopWaiting(from)
+
+		c.mu.RLock()
+		initialized := bean.initialized
+		c.mu.RUnlock()
+		if !initialized {
+			return fmt.Errorf("lazy initialization of bean '%s' failed", name)
+		}
+		return nil
+	}
+
+	err := c.runLazyInit(name, bean, from)
+	c.mu.Unlock()
+	return err
+}
+
+// runLazyInit 创建延迟初始化或容器初始化后注册的bean实例、注入依赖并调用PostConstruct，调用方需持有锁
+// 初始化在新的帧中进行，过程中会临时释放锁，期间其他查找通过lazyInit等待
+func (c *containerImpl) runLazyInit(name string, bean *BeanDefinition, from *initFrame) error {
+	frame := newInitFrame()
+	bean.lazyInit = frame
+	c.waitFor(from, frame)
+	defer func() {
+		c.stopWaiting(from)
+		bean.lazyInit = nil
+		close(frame.done)
+	}()
+
+	c.logger.Debug("延迟初始化bean", zap.String("beanName", name))
+
+	if err := c.createBeanInstance(name, bean, frame); err != nil {
+		c.logger.Error("创建bean实例失败",
+			zap.String("beanName", name),
+			zap.Error(err))
+		return err
+	}
+	if err := c.initializeBean(name, bean, frame); err != nil {
+		return err
+	}
+
+	// 容器已启动时，延迟初始化的bean在初始化完成后立即启动
+	if c.started {
+		c.mu.Unlock()
+		err := c.startBean(name)
+		c.mu.Lock()
+		return err
+	}
+	return nil
+}
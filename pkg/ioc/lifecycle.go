@@ -0,0 +1,176 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// StartableBean 接口定义了对象启动的方法
+type StartableBean interface {
+	// Start 方法在容器启动时调用，按bean的初始化顺序执行
+	Start() error
+}
+
+// Start 按初始化顺序调用所有已初始化单例bean的Start方法
+// 容器启动后再初始化的延迟bean在初始化完成时启动
+func (c *containerImpl) Start() error {
+	c.mu.Lock()
+	if !c.initialized {
+		c.mu.Unlock()
+		return errors.New("container not initialized, call Init() first")
+	}
+	if c.started {
+		c.mu.Unlock()
+		return errors.New("container already started")
+	}
+	c.started = true
+	order := append([]string(nil), c.initOrder...)
+	c.mu.Unlock()
+
+	c.logger.Info("启动IoC容器", zap.Int("beanCount", len(order)))
+	for _, name := range order {
+		if err := c.startBean(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startBean 调用单例bean的Start方法
+func (c *containerImpl) startBean(name string) error {
+	c.mu.RLock()
+	bean := c.beans[name]
+	var instance interface{}
+	if bean != nil && bean.Scope == Singleton {
+		instance = bean.Instance
+	}
+	c.mu.RUnlock()
+
+	startable, ok := instance.(StartableBean)
+	if !ok {
+		return nil
+	}
+
+	c.logger.Debug("启动bean", zap.String("beanName", name))
+	if err := startable.Start(); err != nil {
+		c.logger.Error("启动bean失败",
+			zap.String("beanName", name),
+			zap.Error(err))
+		return fmt.Errorf("error starting bean '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Close 按初始化的逆序调用所有单例bean的Destroy方法，并销毁所有按键实例
+// 某个bean销毁失败时继续销毁其他bean，返回所有错误
+func (c *containerImpl) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return errors.New("container already closed")
+	}
+	c.closed = true
+
+	order := append([]string(nil), c.initOrder...)
+	var keyed []string
+	for name, bean := range c.beans {
+		if bean.Scope == Keyed {
+			keyed = append(keyed, name)
+		}
+	}
+	sort.Strings(keyed)
+	c.mu.Unlock()
+
+	c.logger.Info("关闭IoC容器", zap.Int("beanCount", len(order)))
+
+	var errs []error
+	for _, name := range keyed {
+		if err := c.EvictAllKeys(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		if err := c.destroyBean(order[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// destroyBean 调用单例bean的Destroy方法
+func (c *containerImpl) destroyBean(name string) error {
+	c.mu.RLock()
+	bean := c.beans[name]
+	c.mu.RUnlock()
+
+	if bean == nil || bean.Scope != Singleton {
+		return nil
+	}
+	return c.destroyInstance(name, bean)
+}
+
+// destroyInstance 调用单例bean实例的Destroy方法和声明的销毁方法
+func (c *containerImpl) destroyInstance(name string, bean *BeanDefinition) error {
+	c.mu.RLock()
+	instance := bean.Instance
+	c.mu.RUnlock()
+
+	disposable, ok := instance.(DisposableBean)
+	if instance == nil || (!ok && bean.DestroyMethod == "") {
+		return nil
+	}
+
+	c.logger.Debug("销毁bean", zap.String("beanName", name))
+
+	// 先调用Destroy方法，再调用声明的销毁方法
+	var err error
+	if ok {
+		err = disposable.Destroy()
+	}
+	if err == nil && bean.DestroyMethod != "" {
+		err = callLifecycleMethod(instance, bean.DestroyMethod)
+	}
+	if err != nil {
+		c.logger.Error("销毁bean失败",
+			zap.String("beanName", name),
+			zap.Error(err))
+		return fmt.Errorf("error destroying bean '%s': %w", name, err)
+	}
+	return nil
+}
+
+// validateLifecycleMethods 校验bean声明的初始化方法和销毁方法
+func validateLifecycleMethods(t reflect.Type, bean *BeanDefinition) error {
+	for _, method := range []string{bean.InitMethod, bean.DestroyMethod} {
+		if method == "" {
+			continue
+		}
+		m, ok := t.MethodByName(method)
+		if !ok {
+			return fmt.Errorf("type %s has no method '%s'", t, method)
+		}
+		// 方法类型的第一个参数为接收者
+		mt := m.Type
+		if mt.NumIn() != 1 || mt.NumOut() > 1 || (mt.NumOut() == 1 && mt.Out(0) != errorType) {
+			return fmt.Errorf("lifecycle method %s.%s must have signature func() or func() error, got %s", t, method, mt)
+		}
+	}
+	return nil
+}
+
+// callLifecycleMethod 通过反射调用初始化方法或销毁方法
+func callLifecycleMethod(instance interface{}, method string) error {
+	m := reflect.ValueOf(instance).MethodByName(method)
+	if !m.IsValid() {
+		return fmt.Errorf("type %T has no method '%s'", instance, method)
+	}
+	out := m.Call(nil)
+	if len(out) == 1 && !out[0].IsNil() {
+		return fmt.Errorf("error calling %s: %w", method, out[0].Interface().(error))
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// setter方法名前缀，形如 SetProductService(ProductService) 的方法按推导的名称 productService 自动注入
+// 参数为基本类型的setter不参与自动注入
+const setterPrefix = "Set"
+
+// error接口的反射类型
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// MethodInjection 描述一次显式声明的方法注入
+type MethodInjection struct {
+	// 方法名称
+	Method string
+
+	// 每个参数对应的bean名称，为空字符串时按参数类型查找
+	Dependencies []string
+}
+
+// RegisterMethodInjection 为已注册的bean声明方法注入，只能在容器初始化前调用
+// dependencies依次对应方法的参数，省略或为空字符串的参数按类型解析
+func (c *containerImpl) RegisterMethodInjection(name string, method string, dependencies ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.initialized {
+		return errors.New("cannot modify bean definitions after container initialization")
+	}
+
+	bean, exists := c.beans[name]
+	if !exists {
+		return fmt.Errorf("bean with name '%s' not found", name)
+	}
+
+	if method == "" {
+		return errors.New("method name cannot be empty")
+	}
+
+	// 实例已知时在注册阶段校验方法签名，工厂bean在调用时校验
+	if bean.Instance != nil {
+		m := reflect.ValueOf(bean.Instance).MethodByName(method)
+		if !m.IsValid() {
+			return fmt.Errorf("bean '%s' has no exported method '%s'", name, method)
+		}
+		if err := validateInjectMethod(method, m.Type(), dependencies); err != nil {
+			return err
+		}
+	}
+
+	bean.InjectMethods = append(bean.InjectMethods, MethodInjection{
+		Method:       method,
+		Dependencies: dependencies,
+	})
+	c.logger.Debug("成功注册方法注入",
+		zap.String("beanName", name),
+		zap.String("method", method),
+		zap.Strings("dependencies", dependencies))
+	return nil
+}
+
+// validateInjectMethod 校验注入方法的签名：至少一个参数，无返回值或只返回error
+func validateInjectMethod(method string, mt reflect.Type, dependencies []string) error {
+	if mt.NumIn() == 0 {
+		return fmt.Errorf("method '%s' must accept at least one parameter", method)
+	}
+	if mt.IsVariadic() {
+		return fmt.Errorf("method '%s' must not be variadic", method)
+	}
+	if mt.NumOut() > 1 || (mt.NumOut() == 1 && mt.Out(0) != errorType) {
+		return fmt.Errorf("method '%s' must return nothing or a single error", method)
+	}
+	if len(dependencies) > mt.NumIn() {
+		return fmt.Errorf("method '%s' accepts %d parameters, but %d dependencies were declared",
+			method, mt.NumIn(), len(dependencies))
+	}
+	return nil
+}
+
+// injectMethods 调用显式声明的注入方法和约定命名的setter方法
+func (c *containerImpl) injectMethods(instance interface{}, bean *BeanDefinition, r *dependencyResolver) error {
+	val := reflect.ValueOf(instance)
+
+	// 记录显式声明的方法，避免作为setter重复调用
+	declared := make(map[string]bool)
+
+	if bean != nil {
+		for _, mi := range bean.InjectMethods {
+			declared[mi.Method] = true
+
+			m := val.MethodByName(mi.Method)
+			if !m.IsValid() {
+				return fmt.Errorf("bean '%s' has no exported method '%s'", bean.Name, mi.Method)
+			}
+			if err := validateInjectMethod(mi.Method, m.Type(), mi.Dependencies); err != nil {
+				return err
+			}
+
+			args := make([]reflect.Value, m.Type().NumIn())
+			for i := range args {
+				paramType := m.Type().In(i)
+
+				var dep interface{}
+				var err error
+				if i < len(mi.Dependencies) && mi.Dependencies[i] != "" {
+					dep, err = r.byName(mi.Dependencies[i])
+				} else {
+					dep, err = r.byType(paramType, "")
+				}
+				if err != nil {
+					return fmt.Errorf("error injecting parameter %d of method '%s': %w", i, mi.Method, err)
+				}
+
+				if args[i], err = assignableValue(dep, paramType); err != nil {
+					return fmt.Errorf("error injecting parameter %d of method '%s': %w", i, mi.Method, err)
+				}
+			}
+
+			if err := callInjectMethod(mi.Method, m, args); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 约定命名的setter方法
+	t := val.Type()
+	for i := 0; i < t.NumMethod(); i++ {
+		method := t.Method(i)
+		if declared[method.Name] || !isSetterName(method.Name) || isAwareMethod(instance, method.Name) {
+			continue
+		}
+
+		m := val.Method(i)
+		mt := m.Type()
+		if mt.NumIn() != 1 || isBasicKind(mt.In(0)) || validateInjectMethod(method.Name, mt, nil) != nil {
+			continue
+		}
+
+		arg, ok, err := c.resolveSetterDependency(bean, method.Name, mt.In(0), r)
+		if err != nil {
+			return fmt.Errorf("error injecting setter '%s': %w", method.Name, err)
+		}
+		if !ok {
+			continue
+		}
+
+		if err := callInjectMethod(method.Name, m, []reflect.Value{arg}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveSetterDependency 按方法名推导的bean名称解析setter参数，例如 SetProductService -> productService
+// 找不到该名称的bean时返回ok=false，约定命名的setter不是必需的；其他错误直接返回
+func (c *containerImpl) resolveSetterDependency(bean *BeanDefinition, method string, paramType reflect.Type, r *dependencyResolver) (reflect.Value, bool, error) {
+	name := lowerFirst(strings.TrimPrefix(method, setterPrefix))
+
+	module := ""
+	if bean != nil {
+		module = bean.Module
+	}
+	if !c.hasBean(module, name) {
+		c.logger.Debug("未找到setter依赖，跳过注入",
+			zap.String("method", method),
+			zap.String("dependency", name))
+		return reflect.Value{}, false, nil
+	}
+
+	dep, err := r.byName(name)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+	arg, err := assignableValue(dep, paramType)
+	if err != nil {
+		return reflect.Value{}, false, err
+	}
+	return arg, true, nil
+}
+
+// isBasicKind 判断类型是否为字符串、数值或布尔等基本类型，这类参数的setter不参与约定注入
+func isBasicKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+// callInjectMethod 调用注入方法并处理返回的错误
+func callInjectMethod(name string, m reflect.Value, args []reflect.Value) error {
+	out := m.Call(args)
+	if len(out) == 1 && !out[0].IsNil() {
+		return fmt.Errorf("error invoking method '%s': %w", name, out[0].Interface().(error))
+	}
+	return nil
+}
+
+// assignableValue 将bean转换为可赋值给目标类型的反射值
+func assignableValue(bean interface{}, t reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(bean)
+	if !v.IsValid() {
+		return reflect.Value{}, fmt.Errorf("cannot assign nil bean to %s", t)
+	}
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("bean of type %s is not assignable to %s", v.Type(), t)
+	}
+	return v, nil
+}
+
+// hasBean 判断模块中是否存在可见的指定名称的bean，其他模块的私有bean视为不存在
+func (c *containerImpl) hasBean(module string, name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	resolved, err := c.visibleName(module, name)
+	if err != nil {
+		return false
+	}
+	_, exists := c.beans[resolved]
+	return exists
+}
+
+// isSetterName 判断方法名是否符合 SetXxx 的约定
+func isSetterName(name string) bool {
+	if !strings.HasPrefix(name, setterPrefix) || len(name) == len(setterPrefix) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(setterPrefix):])
+	return unicode.IsUpper(r)
+}
+
+// lowerFirst 将首字母转为小写，例如 ProductService -> productService
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToLower(r)) + s[size:]
+}
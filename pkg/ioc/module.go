@@ -0,0 +1,298 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// 私有bean名称中模块名与bean名称的分隔符，例如 product::productRepository
+// 类型名称中不会出现该分隔符，按命名策略生成的名称（如带包路径的类型名称）不会与私有bean名称混淆
+const moduleSeparator = "::"
+
+// Module 表示一组相关bean的注册，安装到容器时先安装导入的模块
+// 模块的私有bean只能被同一模块的bean注入，同一模块内按名称或类型注入时优先匹配私有bean
+type Module struct {
+	// 模块名称，用作私有bean名称的前缀
+	Name string
+
+	// 模块提供的bean
+	Beans []ModuleBean
+
+	// 模块依赖的外部bean名称，安装时检查是否已注册
+	// 容器初始化前注册的条件bean在Init时才求值，安装时还不存在，不能作为Requires的目标
+	Requires []string
+
+	// 导入的模块，在本模块之前安装
+	Imports []*Module
+}
+
+// ModuleBean 表示模块中的一个bean
+type ModuleBean struct {
+	// bean实例或 func() (interface{}, error) 类型的工厂函数
+	Instance interface{}
+
+	// 注册选项
+	Options []RegisterOption
+
+	// 是否为模块的私有bean
+	Private bool
+}
+
+// Provide 声明模块导出的bean，其他模块和容器中的bean都可以注入
+func Provide(instanceOrFactory interface{}, opts ...RegisterOption) ModuleBean {
+	return ModuleBean{Instance: instanceOrFactory, Options: opts}
+}
+
+// ProvidePrivate 声明模块的私有bean，只有同一模块的bean可以注入
+func ProvidePrivate(instanceOrFactory interface{}, opts ...RegisterOption) ModuleBean {
+	return ModuleBean{Instance: instanceOrFactory, Options: opts, Private: true}
+}
+
+// inModule 将bean注册到模块中
+func inModule(module string, private bool) RegisterOption {
+	return func(r *registration) {
+		r.module = module
+		r.private = private
+	}
+}
+
+// moduleInstall 记录一次Install调用中安装的模块和注册的bean，安装失败时整体撤销
+type moduleInstall struct {
+	// 本次安装的模块名称，按安装顺序排列
+	modules []string
+
+	// 本次注册的bean名称，按注册顺序排列
+	beans []string
+
+	// 本次注册覆盖的、安装前已存在的bean定义
+	replaced map[string]*replacedBean
+}
+
+// Install 安装模块，导入的模块先于本模块安装，同一个模块只安装一次
+// 模块依赖的外部bean在安装时检查；任何模块安装失败时撤销本次调用安装的所有模块，
+// 包括导入的模块、已注册的bean和等待求值的条件bean，被覆盖的bean定义会被恢复
+func (c *containerImpl) Install(module *Module) error {
+	install := &moduleInstall{replaced: make(map[string]*replacedBean)}
+	if err := c.installModule(module, nil, install); err != nil {
+		return errors.Join(err, c.rollbackInstall(install))
+	}
+	return nil
+}
+
+// rollbackInstall 按注册的逆序撤销一次Install调用的结果，返回撤销过程中的错误
+func (c *containerImpl) rollbackInstall(install *moduleInstall) error {
+	var errs []error
+	undone := make(map[string]bool)
+	for i := len(install.beans) - 1; i >= 0; i-- {
+		name := install.beans[i]
+
+		// 同一名称在本次调用中多次注册时只撤销一次
+		if undone[name] {
+			continue
+		}
+		undone[name] = true
+
+		// 覆盖了原定义的bean恢复原定义，而不是移除
+		if replaced, ok := install.replaced[name]; ok {
+			c.mu.Lock()
+			c.restoreBean(replaced)
+			c.mu.Unlock()
+			continue
+		}
+		if err := c.Unregister(name); err != nil {
+			errs = append(errs, fmt.Errorf("error rolling back bean '%s': %w", name, err))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, name := range install.modules {
+		delete(c.modules, name)
+	}
+
+	pending := c.pending[:0:0]
+	for _, p := range c.pending {
+		if !containsString(install.modules, p.bean.Module) {
+			pending = append(pending, p)
+		}
+	}
+	c.pending = pending
+
+	if len(install.modules) > 0 {
+		c.logger.Warn("模块安装失败，已撤销安装",
+			zap.Strings("modules", install.modules),
+			zap.Strings("beans", install.beans))
+	}
+	return errors.Join(errs...)
+}
+
+// installModule 安装模块，path为正在安装的模块导入链，用于检测循环导入
+func (c *containerImpl) installModule(m *Module, path []string, install *moduleInstall) error {
+	if m == nil {
+		return errors.New("cannot install nil module")
+	}
+	if m.Name == "" || strings.Contains(m.Name, moduleSeparator) {
+		return fmt.Errorf("invalid module name '%s'", m.Name)
+	}
+	for _, name := range path {
+		if name == m.Name {
+			return fmt.Errorf("circular module import detected: %s", strings.Join(append(path, m.Name), " -> "))
+		}
+	}
+
+	c.mu.RLock()
+	installed, exists := c.modules[m.Name]
+	c.mu.RUnlock()
+	if exists {
+		if installed == m {
+			return nil
+		}
+		return fmt.Errorf("module '%s' already installed", m.Name)
+	}
+
+	path = append(path, m.Name)
+	for _, imported := range m.Imports {
+		if err := c.installModule(imported, path, install); err != nil {
+			return fmt.Errorf("module '%s': %w", m.Name, err)
+		}
+	}
+
+	// 检查依赖的外部bean
+	c.mu.RLock()
+	var missing []string
+	for _, required := range m.Requires {
+		name := c.followAlias(required)
+		if bean, exists := c.beans[name]; !exists || (bean.Private && bean.Module != m.Name) {
+			missing = append(missing, required)
+		}
+	}
+	c.mu.RUnlock()
+	if len(missing) > 0 {
+		c.logger.Error("模块依赖的bean未注册",
+			zap.String("module", m.Name),
+			zap.Strings("missing", missing))
+		return fmt.Errorf("module '%s' requires beans that are not registered: %v", m.Name, missing)
+	}
+
+	install.modules = append(install.modules, m.Name)
+
+	registered := 0
+	for _, mb := range m.Beans {
+		opts := append(append([]RegisterOption(nil), mb.Options...), inModule(m.Name, mb.Private))
+		bean, replaced, err := c.register(mb.Instance, opts...)
+		if err != nil {
+			return fmt.Errorf("module '%s': %w", m.Name, err)
+		}
+		if bean != nil {
+			// 只记录覆盖本次调用之前就存在的定义
+			if replaced != nil && !containsString(install.beans, bean.Name) {
+				install.replaced[bean.Name] = replaced
+			}
+			install.beans = append(install.beans, bean.Name)
+			registered++
+		}
+	}
+
+	c.mu.Lock()
+	c.modules[m.Name] = m
+	c.mu.Unlock()
+
+	c.logger.Debug("成功安装模块",
+		zap.String("module", m.Name),
+		zap.Int("beanCount", registered))
+	return nil
+}
+
+// localName 返回模块中的名称对应的bean名称，同一模块的私有bean优先，调用方需持有锁
+func (c *containerImpl) localName(module string, name string) string {
+	if module != "" {
+		private := module + moduleSeparator + name
+		if bean, exists := c.beans[private]; exists && bean.Private && bean.Module == module {
+			return private
+		}
+	}
+	return c.followAlias(name)
+}
+
+// visibleName 返回模块中可见的bean名称，名称指向其他模块的私有bean时返回错误，调用方需持有锁
+func (c *containerImpl) visibleName(module string, name string) (string, error) {
+	base, isFactory := trimFactoryBeanPrefix(name)
+	resolved := c.localName(module, base)
+
+	if bean, exists := c.beans[resolved]; exists && bean.Private && bean.Module != module {
+		return "", fmt.Errorf("bean '%s' is private to module '%s'", base, bean.Module)
+	}
+
+	if isFactory {
+		return FactoryBeanPrefix + resolved, nil
+	}
+	return resolved, nil
+}
+
+// privateCandidates 返回模块中类型与t匹配的私有bean名称，调用方需持有锁
+func (c *containerImpl) privateCandidates(module string, t reflect.Type) []string {
+	if module == "" {
+		return nil
+	}
+	var candidates []string
+	for name, bean := range c.beans {
+		if bean.Private && bean.Module == module && c.typeMatches(bean, t) {
+			candidates = append(candidates, name)
+		}
+	}
+	return candidates
+}
+
+// candidatesFor 返回模块中类型与t匹配的bean名称，有匹配的私有bean时只返回私有bean，调用方需持有锁
+func (c *containerImpl) candidatesFor(module string, t reflect.Type) []string {
+	if local := c.privateCandidates(module, t); len(local) > 0 {
+		return local
+	}
+	return c.matchCandidates(t)
+}
+
+// scopedResolver 返回按模块可见性查找依赖的查找方式
+// 同一模块的私有bean优先，其他模块的私有bean不可见
+func (c *containerImpl) scopedResolver(module string, r *dependencyResolver) *dependencyResolver {
+	return &dependencyResolver{
+		byName: func(name string) (interface{}, error) {
+			c.mu.RLock()
+			resolved, err := c.visibleName(module, name)
+			c.mu.RUnlock()
+			if err != nil {
+				return nil, err
+			}
+			return r.byName(resolved)
+		},
+		byType: func(t reflect.Type, qualifier string) (interface{}, error) {
+			c.mu.RLock()
+			var name string
+			var err error
+			local := c.privateCandidates(module, t)
+			if len(local) > 0 {
+				name, err = c.selectCandidate(t, qualifier, local)
+			}
+			c.mu.RUnlock()
+
+			// 私有bean无法唯一确定时按容器中的公共bean查找
+			if len(local) == 0 || err != nil {
+				return r.byType(t, qualifier)
+			}
+			return r.byName(name)
+		},
+		frame: r.frame,
+	}
+}
+
+// displayName 返回错误和依赖关系图中使用的bean名称，模块导出的bean带有模块前缀，调用方需持有锁
+func (c *containerImpl) displayName(name string) string {
+	if bean, exists := c.beans[name]; exists && bean.Module != "" && !bean.Private {
+		return bean.Module + moduleSeparator + name
+	}
+	return name
+}
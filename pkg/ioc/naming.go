@@ -0,0 +1,39 @@
+package ioc
+
+import (
+	"reflect"
+)
+
+// NamingStrategy 根据类型生成bean名称，用于注册时未指定名称的bean
+type NamingStrategy func(t reflect.Type) string
+
+// ShortTypeName 使用不含包路径的类型名称，例如 ServiceImpl，是容器的默认命名策略
+func ShortTypeName(t reflect.Type) string {
+	return simpleTypeName(t)
+}
+
+// QualifiedTypeName 使用带完整包路径的类型名称，例如 github.com/org/app/modules/product/impl.ServiceImpl
+// 用于避免不同包中同名类型的冲突，没有包路径的类型（如 []string）使用其类型字符串
+func QualifiedTypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// LowerCamelTypeName 使用首字母小写的简短类型名称，例如 serviceImpl
+func LowerCamelTypeName(t reflect.Type) string {
+	return lowerFirst(simpleTypeName(t))
+}
+
+// WithNamingStrategy 设置未指定名称时根据类型生成bean名称的策略，默认为ShortTypeName
+func WithNamingStrategy(strategy NamingStrategy) ContainerOption {
+	return func(c *containerImpl) {
+		if strategy != nil {
+			c.naming = strategy
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// 本包函数名的前缀，用于在调用栈中跳过容器内部的调用
+var packageFuncPrefix = reflect.TypeOf(containerImpl{}).PkgPath() + "."
+
+// WithAllowOverride 允许在容器初始化前以同名注册覆盖已有的bean定义，默认关闭
+// 对Register、RegisterConstructor、RegisterKeyedFactory等所有注册方式一致生效
+// 标记为NonOverridable的bean不能被覆盖
+func WithAllowOverride(enabled bool) ContainerOption {
+	return func(c *containerImpl) {
+		c.allowOverride = enabled
+	}
+}
+
+// NonOverridable 将bean标记为不可覆盖，无论容器是否允许覆盖，Override和同名注册都会失败
+func NonOverridable() RegisterOption {
+	return func(r *registration) {
+		r.nonOverridable = true
+	}
+}
+
+// Override 在容器初始化前替换已注册bean的实例或工厂函数，不受WithAllowOverride限制
+// 保留原定义的名称、作用域、限定符等元数据，清除只适用于原类型的初始化方法和销毁方法
+func (c *containerImpl) Override(name string, instanceOrFactory interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.initialized {
+		return fmt.Errorf("cannot override bean '%s' after container initialization", name)
+	}
+	if instanceOrFactory == nil {
+		return errors.New("cannot register nil instance")
+	}
+
+	name = c.followAlias(name)
+	bean, exists := c.beans[name]
+	if !exists {
+		return fmt.Errorf("bean with name '%s' not found", name)
+	}
+	if bean.NonOverridable {
+		return fmt.Errorf("bean '%s' is not overridable", name)
+	}
+	if bean.Scope == Keyed {
+		return fmt.Errorf("keyed bean '%s' cannot be overridden", name)
+	}
+
+	replacement := *bean
+	replacement.Instance = nil
+	replacement.Type = nil
+	replacement.Factory = nil
+	replacement.InitMethod = ""
+	replacement.DestroyMethod = ""
+	replacement.factoryObject = nil
+	replacement.source = callerSite()
+
+	if factory, ok := instanceOrFactory.(func() (interface{}, error)); ok {
+		replacement.Factory = factory
+	} else {
+		t := reflect.TypeOf(instanceOrFactory)
+		if bean.Scope == Prototype && requiresFactory(t) {
+			return fmt.Errorf("prototype bean of kind %s requires a factory, use RegisterFactory", t.Kind())
+		}
+		for _, iface := range bean.Interfaces {
+			if !t.Implements(iface) {
+				return fmt.Errorf("type %s does not implement interface %s", t, iface)
+			}
+		}
+		replacement.Type = t
+		replacement.Instance = instanceOrFactory
+	}
+
+	c.logOverride(bean, &replacement)
+
+	// 原地替换，类型表、接口索引和别名仍然指向同一个定义
+	*bean = replacement
+	return nil
+}
+
+// replacedBean 记录被同名注册覆盖的bean定义及其在类型表中的键，用于撤销覆盖
+type replacedBean struct {
+	// 被覆盖的bean定义
+	bean *BeanDefinition
+
+	// 类型表中指向该定义的键
+	keys []string
+}
+
+// replacedBean 返回同名注册将会覆盖的bean定义，不存在时返回nil，调用方需持有锁
+func (c *containerImpl) replacedBean(name string) *replacedBean {
+	bean, exists := c.beans[name]
+	if !exists {
+		return nil
+	}
+
+	replaced := &replacedBean{bean: bean}
+	for key, registered := range c.typeRegistry[bean.TypeName] {
+		if registered == bean {
+			replaced.keys = append(replaced.keys, key)
+		}
+	}
+	return replaced
+}
+
+// restoreBean 撤销覆盖，用被覆盖的定义替换当前的同名定义，调用方需持有锁
+// 覆盖只能发生在容器初始化前，当前定义尚未创建实例，别名仍然指向同一个名称
+func (c *containerImpl) restoreBean(replaced *replacedBean) {
+	bean := replaced.bean
+	if current, exists := c.beans[bean.Name]; exists {
+		c.unindexBean(bean.Name, current)
+	}
+
+	c.beans[bean.Name] = bean
+	for _, key := range replaced.keys {
+		if _, exists := c.typeRegistry[bean.TypeName]; !exists {
+			c.typeRegistry[bean.TypeName] = make(map[string]*BeanDefinition)
+		}
+		c.typeRegistry[bean.TypeName][key] = bean
+	}
+	for _, iface := range bean.Interfaces {
+		c.typeIndex[iface] = append(c.typeIndex[iface], bean.Name)
+	}
+	c.logger.Warn("恢复被覆盖的bean定义",
+		zap.String("beanName", bean.Name),
+		zap.String("source", bean.source))
+}
+
+// logOverride 记录bean定义被覆盖，包含新旧两处注册位置
+func (c *containerImpl) logOverride(previous *BeanDefinition, current *BeanDefinition) {
+	c.logger.Warn("覆盖bean定义",
+		zap.String("beanName", previous.Name),
+		zap.String("previous", previous.source),
+		zap.String("current", current.source))
+}
+
+// callerSite 返回容器外部调用方的文件和行号
+func callerSite() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packageFuncPrefix) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
@@ -0,0 +1,457 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// In 嵌入到结构体中，表示该结构体是参数对象，其导出字段从容器中解析
+// 参数对象可以作为构造函数或Invoke函数的参数，字段支持以下标签：
+//
+//	name:"productRepository"  按名称解析，否则按类型解析
+//	qualifier:"mysql"         按类型解析时只匹配带有该限定符的bean
+//	optional:"true"           找不到依赖时保留零值
+//	group:"routes"            收集值组中的所有成员，字段必须是切片类型
+type In struct{}
+
+// Out 嵌入到结构体中，表示该结构体是结果对象，其导出字段分别注册为bean
+// 结果对象可以作为构造函数的返回值，字段支持以下标签：
+//
+//	name:"productRepository"  bean名称，默认为首字母小写的字段名
+//	group:"routes"            将字段值加入值组，而不是注册为具名bean
+//
+// 具名bean像其他bean一样完成依赖注入和PostConstruct；值组成员按构造函数返回的原样注入，
+// 容器不会为其注入依赖、调用PostConstruct或销毁方法，需要的依赖应由构造函数的参数传入
+type Out struct{}
+
+var (
+	// In标记的反射类型
+	inType = reflect.TypeOf(In{})
+
+	// Out标记的反射类型
+	outType = reflect.TypeOf(Out{})
+)
+
+// constructorProvider 包装一个构造函数，单例作用域下构造函数只调用一次
+type constructorProvider struct {
+	// 构造函数
+	fn reflect.Value
+
+	// 作用域
+	scope Scope
+
+	// 调用构造函数之前执行的准备工作，参数为发起调用的初始化帧，可以为nil
+	prepare func(from *initFrame) error
+
+	// 保护以下状态的互斥锁
+	mu sync.Mutex
+
+	// 正在调用时非nil，调用在该帧中进行，其他调用通过它等待调用结果
+	calling *initFrame
+
+	// 是否已经调用过（仅单例）
+	done bool
+
+	// 缓存的调用结果（仅单例）
+	result reflect.Value
+}
+
+// groupMember 表示值组中的一个成员，来自某个结果对象的字段
+type groupMember struct {
+	// 提供该成员的构造函数
+	provider *constructorProvider
+
+	// 结果对象中对应字段的下标
+	field int
+}
+
+// RegisterConstructor 注册构造函数，构造函数的参数从容器中解析
+// 构造函数返回 T 或 (T, error)；如果T是结果对象（嵌入了Out），name会被忽略，
+// 结果对象的每个字段分别注册为bean或加入值组。容器初始化后注册的单例立即调用构造函数
+func (c *containerImpl) RegisterConstructor(name string, scope Scope, constructor interface{}) error {
+	if constructor == nil {
+		return errors.New("constructor cannot be nil")
+	}
+
+	fv := reflect.ValueOf(constructor)
+	if err := validateConstructor(fv.Type()); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	live, err := c.registerProvider(name, &constructorProvider{fn: fv, scope: scope})
+	c.mu.Unlock()
+
+	// 撤销注册时销毁已经创建的bean
+	for _, bean := range live {
+		if destroyErr := c.destroyInstance(bean.Name, bean); destroyErr != nil {
+			err = errors.Join(err, destroyErr)
+		}
+	}
+	return err
+}
+
+// registerProvider 将构造函数的结果注册为bean，调用方需持有锁
+// 注册失败时返回被撤销的已初始化bean，由调用方在释放锁后销毁
+func (c *containerImpl) registerProvider(name string, p *constructorProvider) ([]*BeanDefinition, error) {
+	scope := p.scope
+	resultType := p.fn.Type().Out(0)
+
+	// 结果对象，注册多个bean
+	if isResultObject(resultType) {
+		return c.registerResultObject(p, resultType)
+	}
+
+	if name == "" {
+		return nil, errors.New("bean name cannot be empty")
+	}
+
+	construct := func(from *initFrame) (interface{}, error) {
+		result, err := c.callConstructor(p, from)
+		if err != nil {
+			return nil, err
+		}
+		return result.Interface(), nil
+	}
+	err := c.insertBean(&BeanDefinition{
+		Name:  name,
+		Type:  resultType,
+		Scope: scope,
+		Factory: func() (interface{}, error) {
+			return construct(nil)
+		},
+		construct: construct,
+		source:    callerSite(),
+	}, "", false)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("成功注册构造函数",
+		zap.String("beanName", name),
+		zap.String("type", resultType.String()),
+		zap.Int("scope", scope))
+	return nil, nil
+}
+
+// resultField 表示结果对象中注册为具名bean或加入值组的字段
+type resultField struct {
+	// 字段下标
+	index int
+
+	// bean名称或值组名称
+	name string
+}
+
+// resultObjectFields 校验结果对象的字段，按字段顺序返回具名bean和值组成员
+func resultObjectFields(t reflect.Type) (names []resultField, groups []resultField, err error) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == outType {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, nil, fmt.Errorf("field '%s' of result object %s must be exported", field.Name, t)
+		}
+
+		if group := field.Tag.Get("group"); group != "" {
+			groups = append(groups, resultField{index: i, name: group})
+			continue
+		}
+
+		name := field.Tag.Get("name")
+		if name == "" {
+			name = lowerFirst(field.Name)
+		}
+		for _, other := range names {
+			if other.name == name {
+				return nil, nil, fmt.Errorf("result object %s declares bean '%s' more than once", t, name)
+			}
+		}
+		names = append(names, resultField{index: i, name: name})
+	}
+	return names, groups, nil
+}
+
+// registerResultObject 将结果对象的字段注册为bean或值组成员，调用方需持有锁
+// 容器初始化后注册时，任一字段注册失败都会撤销已注册的字段，返回其中已初始化的单例bean，由调用方在释放锁后销毁
+func (c *containerImpl) registerResultObject(p *constructorProvider, t reflect.Type) ([]*BeanDefinition, error) {
+	names, groups, err := resultObjectFields(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先校验所有名称，避免注册一半失败
+	for _, f := range names {
+		if err := c.checkBeanName(f.name); err != nil {
+			return nil, err
+		}
+	}
+
+	var inserted []*BeanDefinition
+	for _, f := range names {
+		index, name := f.index, f.name
+		construct := func(from *initFrame) (interface{}, error) {
+			result, err := c.callConstructor(p, from)
+			if err != nil {
+				return nil, err
+			}
+			return result.Field(index).Interface(), nil
+		}
+		bean := &BeanDefinition{
+			Name:  name,
+			Type:  t.Field(index).Type,
+			Scope: p.scope,
+			Factory: func() (interface{}, error) {
+				return construct(nil)
+			},
+			construct: construct,
+			source:    callerSite(),
+		}
+		if err := c.insertBean(bean, "", false); err != nil {
+			return c.rollbackResultObject(t, inserted), err
+		}
+		inserted = append(inserted, bean)
+		c.logger.Debug("成功注册结果对象字段",
+			zap.String("beanName", name),
+			zap.String("field", t.Field(index).Name))
+	}
+
+	for _, f := range groups {
+		c.groups[f.name] = append(c.groups[f.name], &groupMember{provider: p, field: f.index})
+		c.logger.Debug("成功注册值组成员",
+			zap.String("group", f.name),
+			zap.String("field", t.Field(f.index).Name))
+	}
+
+	return nil, nil
+}
+
+// rollbackResultObject 撤销结果对象已注册的字段，返回其中已初始化的单例bean，调用方需持有锁
+func (c *containerImpl) rollbackResultObject(t reflect.Type, inserted []*BeanDefinition) []*BeanDefinition {
+	var live []*BeanDefinition
+	for i := len(inserted) - 1; i >= 0; i-- {
+		bean := inserted[i]
+		c.removeBean(bean.Name, bean)
+		if bean.Scope == Singleton && bean.initialized {
+			live = append(live, bean)
+		}
+	}
+
+	if len(inserted) > 0 {
+		c.logger.Warn("结果对象注册失败，已撤销已注册的字段", zap.String("type", t.String()))
+	}
+	return live
+}
+
+// callConstructor 调用构造函数，单例作用域下复用第一次调用的结果，from为发起调用的初始化帧
+func (c *containerImpl) callConstructor(p *constructorProvider, from *initFrame) (reflect.Value, error) {
+	if p.prepare != nil {
+		if err := p.prepare(from); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	if p.scope != Singleton {
+		return c.invokeConstructor(p.fn, from)
+	}
+
+	p.mu.Lock()
+	for !p.done && p.calling != nil {
+		// 等待会形成环说明构造函数直接或间接依赖自身
+		calling := p.calling
+		if !c.waitFor(from, calling) {
+			p.mu.Unlock()
+			return reflect.Value{}, fmt.Errorf("circular dependency detected for constructor %s", p.fn.Type())
+		}
+
+		// 其他调用正在进行，等待其完成；调用失败时重试
+		p.mu.Unlock()
+		<-calling.done
+		c.stopWaiting(from)
+		p.mu.Lock()
+	}
+	if p.done {
+		result := p.result
+		p.mu.Unlock()
+		return result, nil
+	}
+	calling := newInitFrame()
+	p.calling = calling
+	p.mu.Unlock()
+
+	c.waitFor(from, calling)
+	result, err := c.invokeConstructor(p.fn, calling)
+	c.stopWaiting(from)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calling = nil
+	close(calling.done)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	p.done = true
+	p.result = result
+	return result, nil
+}
+
+// invokeConstructor 解析参数并调用构造函数，from为发起调用的初始化帧
+func (c *containerImpl) invokeConstructor(fv reflect.Value, from *initFrame) (reflect.Value, error) {
+	ft := fv.Type()
+	r := c.currentResolver(from)
+
+	args := make([]reflect.Value, ft.NumIn())
+	for i := range args {
+		arg, err := c.resolveParam(ft.In(i), r)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("error resolving parameter %d of constructor %s: %w", i, ft, err)
+		}
+		args[i] = arg
+	}
+
+	out := fv.Call(args)
+	if len(out) == 2 && !out[1].IsNil() {
+		return reflect.Value{}, out[1].Interface().(error)
+	}
+
+	result := out[0]
+	switch result.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		if result.IsNil() {
+			return reflect.Value{}, fmt.Errorf("constructor %s returned nil", ft)
+		}
+	}
+	return result, nil
+}
+
+// currentResolver 根据容器当前所处的阶段选择依赖查找方式
+// 初始化过程中发起的查找（from非nil）始终使用初始化时的查找方式，以便检测跨越多次初始化的循环依赖
+func (c *containerImpl) currentResolver(from *initFrame) *dependencyResolver {
+	c.mu.RLock()
+	phase := c.currentPhase
+	c.mu.RUnlock()
+
+	if from != nil || phase == NotInitialized || phase == InjectionPhase {
+		return c.initResolver(from)
+	}
+	return c.runtimeResolver()
+}
+
+// resolveParamObject 解析参数对象的所有字段
+func (c *containerImpl) resolveParamObject(t reflect.Type, r *dependencyResolver) (reflect.Value, error) {
+	obj := reflect.New(t).Elem()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type == inType {
+			continue
+		}
+		if !field.IsExported() {
+			return reflect.Value{}, fmt.Errorf("field '%s' of parameter object %s must be exported", field.Name, t)
+		}
+
+		optional := field.Tag.Get("optional") == "true"
+
+		var v reflect.Value
+		var err error
+		if group := field.Tag.Get("group"); group != "" {
+			v, err = c.resolveGroup(group, field.Type, r.frame)
+		} else if name := field.Tag.Get("name"); name != "" {
+			var dep interface{}
+			if dep, err = r.byName(name); err == nil {
+				v, err = assignableValue(dep, field.Type)
+			}
+		} else if qualifier := field.Tag.Get("qualifier"); qualifier != "" {
+			var dep interface{}
+			if dep, err = r.byType(field.Type, qualifier); err == nil {
+				v, err = assignableValue(dep, field.Type)
+			}
+		} else {
+			v, err = c.resolveParam(field.Type, r)
+		}
+
+		if err != nil {
+			if optional {
+				continue
+			}
+			return reflect.Value{}, fmt.Errorf("error resolving field '%s' of %s: %w", field.Name, t, err)
+		}
+
+		obj.Field(i).Set(v)
+	}
+
+	return obj, nil
+}
+
+// resolveGroup 收集值组中的所有成员，按注册顺序组成切片，from为发起查找的初始化帧
+// 成员是构造函数返回的原始值，不经过依赖注入和PostConstruct
+func (c *containerImpl) resolveGroup(group string, t reflect.Type, from *initFrame) (reflect.Value, error) {
+	if t.Kind() != reflect.Slice {
+		return reflect.Value{}, fmt.Errorf("value group '%s' must be injected into a slice, got %s", group, t)
+	}
+
+	c.mu.RLock()
+	members := append([]*groupMember(nil), c.groups[group]...)
+	c.mu.RUnlock()
+
+	values := reflect.MakeSlice(t, 0, len(members))
+	for _, m := range members {
+		result, err := c.callConstructor(m.provider, from)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("error resolving value group '%s': %w", group, err)
+		}
+
+		v := result.Field(m.field)
+		if !v.Type().AssignableTo(t.Elem()) {
+			return reflect.Value{}, fmt.Errorf("value group '%s' member of type %s is not assignable to %s",
+				group, v.Type(), t.Elem())
+		}
+		values = reflect.Append(values, v)
+	}
+
+	return values, nil
+}
+
+// validateConstructor 校验构造函数签名：返回 T 或 (T, error)
+func validateConstructor(ft reflect.Type) error {
+	if ft.Kind() != reflect.Func {
+		return fmt.Errorf("constructor must be a function, got %s", ft.Kind())
+	}
+	if ft.IsVariadic() {
+		return fmt.Errorf("constructor %s must not be variadic", ft)
+	}
+	if ft.NumOut() == 0 || ft.NumOut() > 2 || ft.Out(0) == errorType {
+		return fmt.Errorf("constructor %s must return a value and an optional error", ft)
+	}
+	if ft.NumOut() == 2 && ft.Out(1) != errorType {
+		return fmt.Errorf("the second result of constructor %s must be an error", ft)
+	}
+	return nil
+}
+
+// isParamObject 判断类型是否为嵌入了In的参数对象
+func isParamObject(t reflect.Type) bool {
+	return embedsMarker(t, inType)
+}
+
+// isResultObject 判断类型是否为嵌入了Out的结果对象
+func isResultObject(t reflect.Type) bool {
+	return embedsMarker(t, outType)
+}
+
+// embedsMarker 判断结构体是否直接嵌入了指定的标记类型
+func embedsMarker(t reflect.Type, marker reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == marker {
+			return true
+		}
+	}
+	return false
+}
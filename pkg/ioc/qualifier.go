@@ -0,0 +1,121 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+// SetPrimary 将bean标记为首选bean，只能在容器初始化前调用
+// 按类型注入时如果有多个候选bean，优先选择首选bean
+func (c *containerImpl) SetPrimary(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.initialized {
+		return errors.New("cannot modify bean definitions after container initialization")
+	}
+
+	bean, exists := c.beans[name]
+	if !exists {
+		return fmt.Errorf("bean with name '%s' not found", name)
+	}
+
+	bean.Primary = true
+	c.logger.Debug("成功标记首选bean", zap.String("beanName", name))
+	return nil
+}
+
+// SetQualifiers 为bean添加限定符，只能在容器初始化前调用
+// 字段可以通过 qualifier 标签只匹配带有指定限定符的bean，例如：
+//
+//	Repo product.ProductRepository `inject:"" qualifier:"mysql"`
+func (c *containerImpl) SetQualifiers(name string, qualifiers ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.initialized {
+		return errors.New("cannot modify bean definitions after container initialization")
+	}
+
+	bean, exists := c.beans[name]
+	if !exists {
+		return fmt.Errorf("bean with name '%s' not found", name)
+	}
+
+	for _, qualifier := range qualifiers {
+		if qualifier == "" {
+			return errors.New("qualifier cannot be empty")
+		}
+		if !bean.hasQualifier(qualifier) {
+			bean.Qualifiers = append(bean.Qualifiers, qualifier)
+		}
+	}
+
+	c.logger.Debug("成功添加bean限定符",
+		zap.String("beanName", name),
+		zap.Strings("qualifiers", bean.Qualifiers))
+	return nil
+}
+
+// hasQualifier 判断bean是否带有指定的限定符，bean名称本身也视为限定符
+func (b *BeanDefinition) hasQualifier(qualifier string) bool {
+	if b.Name == qualifier {
+		return true
+	}
+	for _, q := range b.Qualifiers {
+		if q == qualifier {
+			return true
+		}
+	}
+	return false
+}
+
+// selectCandidate 从候选bean中选出唯一的一个，调用方需持有锁
+// 指定了限定符时只保留匹配的候选bean，接口有显式绑定的候选时只在其中选择；仍有多个候选时选择唯一的首选bean
+func (c *containerImpl) selectCandidate(t reflect.Type, qualifier string, candidates []string) (string, error) {
+	sort.Strings(candidates)
+
+	if qualifier != "" {
+		var qualified []string
+		for _, name := range candidates {
+			if c.beans[name].hasQualifier(qualifier) {
+				qualified = append(qualified, name)
+			}
+		}
+		if len(qualified) == 0 {
+			return "", fmt.Errorf("no bean candidate found for type %s with qualifier '%s'", t, qualifier)
+		}
+		candidates = qualified
+	}
+
+	// 限定符筛选之后，接口有显式绑定的候选时只在绑定的bean中选择
+	candidates = c.boundCandidates(t, candidates)
+
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no bean candidate found for type %s", t)
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+
+	var primaries []string
+	for _, name := range candidates {
+		if c.beans[name].Primary {
+			primaries = append(primaries, name)
+		}
+	}
+
+	switch len(primaries) {
+	case 1:
+		return primaries[0], nil
+	case 0:
+		return "", fmt.Errorf("multiple bean candidates found for type %s: %v, mark one as primary or use a qualifier", t, candidates)
+	default:
+		return "", fmt.Errorf("multiple primary bean candidates found for type %s: %v", t, primaries)
+	}
+}
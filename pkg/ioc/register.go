@@ -0,0 +1,373 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/zap"
+)
+
+// RegisterOption bean注册选项
+type RegisterOption func(*registration)
+
+// registration 汇总一次注册的所有选项
+type registration struct {
+	// bean名称
+	name string
+
+	// 类型名称，typed为true时同时注册到类型表
+	typeName string
+
+	// 是否按类型注册
+	typed bool
+
+	// 作用域
+	scope Scope
+
+	// 是否为首选bean
+	primary bool
+
+	// 限定符
+	qualifiers []string
+
+	// 标签
+	labels map[string]string
+
+	// 显式绑定的接口
+	interfaces []reflect.Type
+
+	// 是否延迟初始化，为nil时使用容器的默认设置
+	lazy *bool
+
+	// 声明依赖的其他bean
+	dependsOn []string
+
+	// 初始化方法名
+	initMethod string
+
+	// 销毁方法名
+	destroyMethod string
+
+	// 是否禁止覆盖
+	nonOverridable bool
+
+	// 所属模块
+	module string
+
+	// 是否为模块的私有bean
+	private bool
+
+	// 注册条件
+	conditions []Condition
+
+	// 选项中的错误，在注册时返回
+	err error
+}
+
+// Name 指定bean名称
+// 未指定时按容器的命名策略生成，默认使用简短类型名；同时指定了非空的TypeName时bean名称为 typeName:name
+func Name(name string) RegisterOption {
+	return func(r *registration) {
+		r.name = name
+	}
+}
+
+// TypeName 指定类型名称（如 service, repository 等），bean同时注册到类型表，可通过GetByType获取
+func TypeName(typeName string) RegisterOption {
+	return func(r *registration) {
+		r.typeName = typeName
+		r.typed = true
+	}
+}
+
+// InScope 指定bean的作用域，默认为Singleton
+func InScope(scope Scope) RegisterOption {
+	return func(r *registration) {
+		r.scope = scope
+	}
+}
+
+// Primary 将bean标记为首选bean
+func Primary() RegisterOption {
+	return func(r *registration) {
+		r.primary = true
+	}
+}
+
+// Qualifiers 为bean添加限定符
+func Qualifiers(qualifiers ...string) RegisterOption {
+	return func(r *registration) {
+		r.qualifiers = append(r.qualifiers, qualifiers...)
+	}
+}
+
+// Labels 为bean添加标签，用于描述和筛选bean
+func Labels(labels map[string]string) RegisterOption {
+	return func(r *registration) {
+		if r.labels == nil {
+			r.labels = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			r.labels[k] = v
+		}
+	}
+}
+
+// As 声明bean以哪些接口对外暴露，参数为指向接口的指针，例如 As(new(product.ProductService))
+// 按接口类型查找时，绑定了该接口的bean优先于其他实现了该接口的bean
+func As(interfaces ...interface{}) RegisterOption {
+	return func(r *registration) {
+		for _, iface := range interfaces {
+			t := reflect.TypeOf(iface)
+			if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Interface {
+				r.err = fmt.Errorf("As expects pointers to interfaces, got %v", t)
+				return
+			}
+			r.interfaces = append(r.interfaces, t.Elem())
+		}
+	}
+}
+
+// AsType 声明bean以接口I对外暴露，是As的泛型版本
+func AsType[I any]() RegisterOption {
+	return As((*I)(nil))
+}
+
+// Lazy 指定单例bean是否延迟初始化，覆盖容器的默认设置
+// 延迟初始化的bean在Init时不创建，首次获取或被注入时才创建、注入依赖并调用PostConstruct
+func Lazy(lazy bool) RegisterOption {
+	return func(r *registration) {
+		r.lazy = &lazy
+	}
+}
+
+// DependsOn 声明bean依赖的其他bean，这些bean在该bean创建之前完成创建、依赖注入和PostConstruct
+func DependsOn(names ...string) RegisterOption {
+	return func(r *registration) {
+		r.dependsOn = append(r.dependsOn, names...)
+	}
+}
+
+// InitMethod 指定初始化方法名，在依赖注入和PostConstruct之后通过反射调用
+// 方法签名必须为 func() 或 func() error，适用于无法实现InitializingBean的第三方类型
+func InitMethod(method string) RegisterOption {
+	return func(r *registration) {
+		r.initMethod = method
+	}
+}
+
+// DestroyMethod 指定销毁方法名，在容器关闭时通过反射调用
+// 方法签名必须为 func() 或 func() error，适用于无法实现DisposableBean的第三方类型
+func DestroyMethod(method string) RegisterOption {
+	return func(r *registration) {
+		r.destroyMethod = method
+	}
+}
+
+// RegisterWith 使用注册选项注册bean
+// instanceOrFactory为 func() (interface{}, error) 类型时作为工厂函数，否则作为实例
+func (c *containerImpl) RegisterWith(instanceOrFactory interface{}, opts ...RegisterOption) error {
+	_, _, err := c.register(instanceOrFactory, opts...)
+	return err
+}
+
+// register 使用注册选项注册bean，返回注册的bean定义和被覆盖的原定义，条件bean未注册时返回nil
+func (c *containerImpl) register(instanceOrFactory interface{}, opts ...RegisterOption) (*BeanDefinition, *replacedBean, error) {
+	r := &registration{scope: Singleton}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bean, key, err := c.newBeanDefinition(instanceOrFactory, r)
+	if err != nil {
+		c.logger.Error("注册bean失败",
+			zap.String("beanName", r.name),
+			zap.Error(err))
+		return nil, nil, err
+	}
+
+	// 带有条件的bean在容器初始化时求值条件，初始化后注册时立即求值
+	if len(r.conditions) > 0 {
+		if !c.initialized {
+			c.pending = append(c.pending, &pendingRegistration{
+				bean:       bean,
+				key:        key,
+				typed:      r.typed,
+				conditions: r.conditions,
+			})
+			c.logger.Debug("bean注册条件将在初始化时求值", zap.String("beanName", bean.Name))
+			return nil, nil, nil
+		}
+		if !c.evaluateConditions(bean, r.conditions) {
+			return nil, nil, nil
+		}
+	}
+
+	replaced := c.replacedBean(bean.Name)
+	if err := c.insertBean(bean, key, r.typed); err != nil {
+		return nil, nil, err
+	}
+	return bean, replaced, nil
+}
+
+// newBeanDefinition 根据注册选项创建bean定义，返回bean定义和类型表中的键
+func (c *containerImpl) newBeanDefinition(instanceOrFactory interface{}, r *registration) (*BeanDefinition, string, error) {
+	if instanceOrFactory == nil {
+		return nil, "", errors.New("cannot register nil instance")
+	}
+
+	bean := &BeanDefinition{
+		TypeName:   r.typeName,
+		Scope:      r.scope,
+		Primary:    r.primary,
+		Qualifiers: r.qualifiers,
+		Labels:     r.labels,
+		Interfaces: r.interfaces,
+		DependsOn:  r.dependsOn,
+
+		InitMethod:     r.initMethod,
+		DestroyMethod:  r.destroyMethod,
+		NonOverridable: r.nonOverridable,
+		Module:         r.module,
+		Private:        r.private,
+
+		source: callerSite(),
+	}
+
+	if r.lazy != nil {
+		if r.scope != Singleton {
+			return nil, "", errors.New("lazy initialization only applies to singleton beans")
+		}
+		bean.Lazy = *r.lazy
+		bean.lazySet = true
+	}
+
+	key := r.name
+	if factory, ok := instanceOrFactory.(func() (interface{}, error)); ok {
+		if r.name == "" {
+			return nil, "", errors.New("factory bean must have a name")
+		}
+		bean.Factory = factory
+	} else {
+		t := reflect.TypeOf(instanceOrFactory)
+
+		// 引用类型的多例bean无法通过复制创建，需要使用工厂函数
+		if r.scope == Prototype && requiresFactory(t) {
+			return nil, "", fmt.Errorf("prototype bean of kind %s requires a factory, use RegisterFactory", t.Kind())
+		}
+
+		// 实例必须实现所有绑定的接口，工厂bean在创建实例后校验
+		for _, iface := range r.interfaces {
+			if !t.Implements(iface) {
+				return nil, "", fmt.Errorf("type %s does not implement interface %s", t, iface)
+			}
+		}
+
+		// 工厂bean的生命周期方法在创建实例后校验
+		if err := validateLifecycleMethods(t, bean); err != nil {
+			return nil, "", err
+		}
+
+		bean.Type = t
+		bean.Instance = instanceOrFactory
+
+		// 未指定名称时按命名策略生成，默认使用简短类型名（不含包路径）
+		if key == "" {
+			key = c.naming(t)
+			if key == "" {
+				return nil, "", fmt.Errorf("naming strategy returned empty name for type %s", t)
+			}
+		}
+	}
+
+	bean.Name = key
+	if r.typed && r.typeName != "" {
+		bean.Name = r.typeName + ":" + key
+	}
+
+	// 私有bean的名称带有模块前缀，不同模块的私有bean可以同名
+	if r.private {
+		bean.Name = r.module + moduleSeparator + bean.Name
+	}
+
+	for _, dep := range r.dependsOn {
+		if dep == "" || dep == bean.Name {
+			return nil, "", fmt.Errorf("invalid depends-on '%s' for bean '%s'", dep, bean.Name)
+		}
+	}
+
+	return bean, key, nil
+}
+
+// checkBeanName 检查名称能否用于注册新的bean定义，调用方需持有锁
+// 同名bean不允许覆盖或名称已被用作别名时返回错误
+func (c *containerImpl) checkBeanName(name string) error {
+	if existing, exists := c.beans[name]; exists {
+		if !c.allowOverride || existing.NonOverridable || c.initialized {
+			return fmt.Errorf("bean with name '%s' already exists", name)
+		}
+	}
+	if target, exists := c.aliases[name]; exists {
+		return fmt.Errorf("bean name '%s' is already an alias of '%s'", name, target)
+	}
+	return nil
+}
+
+// addBean 将bean定义加入容器，typed为true时同时注册到类型表，调用方需持有锁
+// 所有注册方式都通过它写入容器，保证名称冲突、别名冲突和覆盖策略一致
+func (c *containerImpl) addBean(bean *BeanDefinition, key string, typed bool) error {
+	if err := c.checkBeanName(bean.Name); err != nil {
+		c.logger.Error("bean名称不可用",
+			zap.String("beanName", bean.Name),
+			zap.Error(err))
+		return err
+	}
+
+	// 类型表中的同名条目属于被覆盖的原定义时按覆盖策略处理，已由checkBeanName校验
+	existing, overriding := c.beans[bean.Name]
+	if typed {
+		if registered, exists := c.typeRegistry[bean.TypeName][key]; exists && !(overriding && registered == existing) {
+			return fmt.Errorf("type '%s' already has a bean with name '%s'", bean.TypeName, key)
+		}
+	}
+
+	// 允许覆盖时移除原定义
+	if overriding {
+		c.logOverride(existing, bean)
+		c.unindexBean(bean.Name, existing)
+	}
+
+	// 注册到总表
+	c.beans[bean.Name] = bean
+
+	// 注册到类型表
+	if typed {
+		if _, exists := c.typeRegistry[bean.TypeName]; !exists {
+			c.typeRegistry[bean.TypeName] = make(map[string]*BeanDefinition)
+		}
+		c.typeRegistry[bean.TypeName][key] = bean
+	}
+
+	// 注册到接口索引
+	for _, iface := range bean.Interfaces {
+		c.typeIndex[iface] = append(c.typeIndex[iface], bean.Name)
+	}
+
+	typeString := "factory"
+	if bean.Type != nil {
+		typeString = bean.Type.String()
+	}
+	c.logger.Debug("成功注册bean",
+		zap.String("beanName", bean.Name),
+		zap.String("typeName", bean.TypeName),
+		zap.String("type", typeString),
+		zap.Int("scope", bean.Scope))
+	return nil
+}
@@ -9,4 +9,7 @@ const (
 
 	// Prototype 表示多例模式，每次获取依赖时创建新实例
 	Prototype
+
+	// Keyed 表示按键多例模式，每个键对应一个实例，通过GetWithKey获取
+	Keyed
 )
@@ -0,0 +1,124 @@
+package ioc
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Validate 校验所有bean的依赖关系而不创建实例
+// 检查inject标签和方法注入声明的依赖是否存在，按类型注入的依赖是否能唯一确定；
+// 类型尚未确定的工厂bean无法校验，将被跳过
+func (c *containerImpl) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.beans))
+	for name := range c.beans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	if err := c.checkAliases(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.checkDependsOn(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, name := range names {
+		errs = append(errs, c.validateBean(name, c.beans[name])...)
+	}
+	return errors.Join(errs...)
+}
+
+// validateBean 校验单个bean的依赖关系，调用方需持有锁
+func (c *containerImpl) validateBean(name string, bean *BeanDefinition) []error {
+	var errs []error
+
+	// 方法注入声明的依赖必须存在
+	for _, mi := range bean.InjectMethods {
+		for _, dep := range mi.Dependencies {
+			if dep == "" {
+				continue
+			}
+			if err := c.validateNamedDependency(bean.Module, dep); err != nil {
+				errs = append(errs, fmt.Errorf("bean '%s' method '%s': %w", name, mi.Method, err))
+			}
+		}
+	}
+
+	t := bean.Type
+	if t == nil {
+		return errs
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return errs
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		injectTag, ok := field.Tag.Lookup("inject")
+		if !ok || field.Tag.Get("optional") == "true" {
+			continue
+		}
+
+		var err error
+		if injectTag == "" {
+			err = c.validateTypedDependency(bean.Module, field.Type, field.Tag.Get("qualifier"))
+		} else {
+			err = c.validateNamedDependency(bean.Module, injectTag)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("bean '%s' field '%s': %w", name, field.Name, err))
+		}
+	}
+
+	return errs
+}
+
+// validateNamedDependency 校验模块中按名称注入的依赖是否存在且可见，调用方需持有锁
+func (c *containerImpl) validateNamedDependency(module string, name string) error {
+	name, err := c.visibleName(module, name)
+	if err != nil {
+		return err
+	}
+	name, _ = trimFactoryBeanPrefix(name)
+	bean, exists := c.beans[name]
+	if !exists {
+		return fmt.Errorf("bean with name '%s' not found", name)
+	}
+	if bean.Scope == Keyed {
+		return fmt.Errorf("bean '%s' is keyed and cannot be injected", name)
+	}
+	return nil
+}
+
+// validateTypedDependency 校验模块中按类型注入的依赖是否能唯一确定，调用方需持有锁
+func (c *containerImpl) validateTypedDependency(module string, t reflect.Type, qualifier string) error {
+	if t == containerType {
+		return nil
+	}
+	candidates := c.candidatesFor(module, t)
+	if len(candidates) == 0 && c.hasUntypedBeans() {
+		// 工厂bean的类型在创建实例后才能确定，此时无法判断依赖是否缺失
+		return nil
+	}
+	_, err := c.selectCandidate(t, qualifier, candidates)
+	return err
+}
+
+// hasUntypedBeans 判断是否存在类型尚未确定的工厂bean，调用方需持有锁
+func (c *containerImpl) hasUntypedBeans() bool {
+	for _, bean := range c.beans {
+		if bean.Type == nil && bean.Factory != nil {
+			return true
+		}
+	}
+	return false
+}